@@ -0,0 +1,208 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/idelchi/godyl/internal/manifest"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// Folder is the folder, relative to the manifest folder, in which the backups are stored.
+const Folder = "backups"
+
+// layout names the folder of each backup, sorting chronologically.
+const layout = "20060102-150405.000000000"
+
+// ErrNoBackup is returned when rolling back a tool without backups.
+var ErrNoBackup = errors.New("no backup found")
+
+// Executable is an executable kept in a backup, along with its aliases.
+type Executable struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Backup holds the executables of a tool as they were before being replaced.
+type Backup struct {
+	Tool        string       `json:"tool"`
+	Time        time.Time    `json:"time"`
+	Executables []Executable `json:"executables"`
+
+	dir string
+}
+
+// Dir returns the folder holding the backups of the tool installed into the output folder.
+func Dir(output, tool string) string {
+	return filepath.Join(output, manifest.Folder, Folder, tool)
+}
+
+// Create copies the executables which exist in the output folder into a new backup of the tool,
+// and removes the oldest backups beyond the number to keep.
+// Nothing is backed up if none of the executables exist as regular files.
+func Create(output, tool string, executables []Executable, keep int) error {
+	backup := Backup{Tool: tool, Time: time.Now()}
+	backup.dir = filepath.Join(Dir(output, tool), backup.Time.UTC().Format(layout))
+
+	for _, executable := range executables {
+		source := filepath.Join(output, executable.Name)
+
+		if info, err := os.Lstat(source); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		if err := copyFile(source, filepath.Join(backup.dir, executable.Name)); err != nil {
+			return fmt.Errorf("backing up %q: %w", source, err)
+		}
+
+		backup.Executables = append(backup.Executables, executable)
+	}
+
+	if len(backup.Executables) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(backup.dir, "backup.json"), data, 0o644); err != nil {
+		return fmt.Errorf("writing backup of %q: %w", tool, err)
+	}
+
+	return prune(output, tool, keep)
+}
+
+// List returns the backups of the tool, the most recent first.
+func List(output, tool string) ([]Backup, error) {
+	dir := Dir(output, tool)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var backups []Backup
+
+	for _, entry := range slices.Backward(entries) {
+		if !entry.IsDir() {
+			continue
+		}
+
+		backup := Backup{dir: filepath.Join(dir, entry.Name())}
+
+		data, err := os.ReadFile(filepath.Join(backup.dir, "backup.json"))
+		if err != nil {
+			return nil, fmt.Errorf("reading backup %q: %w", backup.dir, err)
+		}
+
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return nil, fmt.Errorf("parsing backup %q: %w", backup.dir, err)
+		}
+
+		backups = append(backups, backup)
+	}
+
+	return backups, nil
+}
+
+// Rollback restores the most recent backup of the tool into the output folder, including the aliases,
+// all or nothing, and removes the backup, so that rolling back again restores the one before.
+func Rollback(output, tool string) (Backup, error) {
+	backups, err := List(output, tool)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("%w: %q in %q", ErrNoBackup, tool, Dir(output, tool))
+	}
+
+	backup := backups[0]
+
+	var tx file.Transaction
+	defer tx.Discard()
+
+	for _, executable := range backup.Executables {
+		source := file.NewFile(backup.dir, executable.Name)
+		target := file.NewFile(output, executable.Name)
+
+		info, err := os.Stat(source.String())
+		if err != nil {
+			return backup, err
+		}
+
+		if err := tx.Copy(source, target, info.Mode().Perm()); err != nil {
+			return backup, err
+		}
+
+		if err := tx.Symlink(target, file.NewFiles(output, executable.Aliases...)...); err != nil {
+			return backup, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return backup, fmt.Errorf("restoring %q: %w", tool, err)
+	}
+
+	return backup, os.RemoveAll(backup.dir)
+}
+
+// prune removes the oldest backups of the tool, keeping the given number.
+func prune(output, tool string, keep int) error {
+	backups, err := List(output, tool)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(keep, len(backups)):] {
+		if err := os.RemoveAll(backup.dir); err != nil {
+			return fmt.Errorf("removing backup %q: %w", backup.dir, err)
+		}
+	}
+
+	return nil
+}
+
+// copyFile copies the regular file, preserving its permissions.
+func copyFile(source, target string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+
+		return err
+	}
+
+	return out.Close()
+}
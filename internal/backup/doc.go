@@ -0,0 +1,2 @@
+// Package backup keeps copies of the executables godyl replaces, so that an installation can be rolled back.
+package backup
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/internal/tools"
+)
+
+// chooser prompts the user to resolve ambiguous asset matches.
+// Prompts are serialized, as tools are processed concurrently.
+type chooser struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+
+	// save indicates whether choices can be written back into the tools files.
+	save bool
+	// hints holds the choices to write back, by the file and index the tool was defined at.
+	hints map[tools.Origin]match.Hint
+}
+
+// newChooser returns a chooser if godyl runs in an interactive terminal, otherwise nil.
+func newChooser(save bool) *chooser {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+
+	return &chooser{
+		in:    bufio.NewReader(os.Stdin),
+		out:   os.Stderr,
+		save:  save,
+		hints: make(map[tools.Origin]match.Hint),
+	}
+}
+
+// For returns the match.Chooser for the tool.
+func (c *chooser) For(tool *tools.Tool) match.Chooser {
+	if c == nil {
+		return nil
+	}
+
+	return func(candidates match.Results) (match.Result, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		fmt.Fprintf(c.out, "\n%s: multiple assets match equally well:\n", tool.Name)
+
+		for i, candidate := range candidates {
+			fmt.Fprintf(c.out, "  [%d] %s\n", i+1, candidate.Asset.Name)
+		}
+
+		choice := c.ask(fmt.Sprintf("pick an asset [1-%d], or press enter to skip: ", len(candidates)))
+
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > len(candidates) {
+			return match.Result{}, fmt.Errorf("%w: no asset was chosen", match.ErrAmbiguous)
+		}
+
+		chosen := candidates[n-1]
+
+		// Remote tools files cannot be written back to.
+		if c.save && !tool.Origin.Remote() && confirm(c.ask("save the choice to the tools file? [y/N]: ")) {
+			c.hints[tool.Origin] = hintFor(tool, chosen.Asset.Name)
+		}
+
+		return chosen, nil
+	}
+}
+
+// ask prints the prompt and returns the trimmed answer.
+func (c *chooser) ask(prompt string) string {
+	fmt.Fprint(c.out, prompt)
+
+	answer, _ := c.in.ReadString('\n')
+
+	return strings.TrimSpace(answer)
+}
+
+// Save writes the collected choices as mandatory hints into the tools files the tools were defined in,
+// returning the files written to.
+func (c *chooser) Save() ([]string, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	files := make(map[string]map[int]match.Hint)
+
+	for origin, hint := range c.hints {
+		if files[origin.File] == nil {
+			files[origin.File] = make(map[int]match.Hint)
+		}
+
+		files[origin.File][origin.Index] = hint
+	}
+
+	var saved []string
+
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		if err := tools.SaveHints(path, files[path]); err != nil {
+			return saved, fmt.Errorf("%q: %w", path, err)
+		}
+
+		saved = append(saved, path)
+	}
+
+	return saved, nil
+}
+
+// confirm checks whether the answer is affirmative.
+func confirm(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// hintFor returns a mandatory hint matching the asset exactly on the current platform.
+// The version is templated to keep the hint valid across releases, and other platforms
+// get an empty pattern, which matches any asset.
+func hintFor(tool *tools.Tool, asset string) match.Hint {
+	// Hints are matched against the lowercase asset name
+	name := strings.ToLower(asset)
+	version := strings.ToLower(tool.Version.Version)
+
+	var parts []string
+
+	placeholder := "{{ .Version }}"
+
+	switch trimmed := strings.TrimPrefix(version, "v"); {
+	case version != "" && strings.Contains(name, version):
+		parts = strings.Split(name, version)
+	case trimmed != "" && strings.Contains(name, trimmed):
+		parts = strings.Split(name, trimmed)
+		placeholder = `{{ trimPrefix "v" .Version }}`
+	default:
+		parts = []string{name}
+	}
+
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	pattern := "^" + strings.Join(parts, placeholder) + "$"
+
+	return match.Hint{
+		Pattern: fmt.Sprintf(`{{ if and (eq .OS %q) (eq .ARCH %q) }}%s{{ end }}`,
+			tool.Platform.OS.String(), tool.Platform.Architecture.Type, pattern),
+		Must: true,
+	}
+}
@@ -1 +1,91 @@
 package commands_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/idelchi/godyl/internal/commands"
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/tools"
+)
+
+func TestOutcome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		previous string
+		version  string
+		existed  bool
+		err      error
+		dry      bool
+		status   report.Status
+		category string
+	}{
+		{name: "new", version: "v1.0.0", status: report.Installed},
+		{name: "newer version", previous: "1.0.0", version: "v2.0.0", existed: true, status: report.Upgraded},
+		{name: "same version", previous: "1.0.0", version: "v1.0.0", existed: true, status: report.Reinstalled},
+		{name: "same unparsable version", previous: "nightly", version: "nightly", existed: true, status: report.Reinstalled},
+		{name: "unknown previous version", version: "v2.0.0", existed: true, status: report.Reinstalled},
+		{name: "dry run", previous: "1.0.0", version: "v2.0.0", existed: true, dry: true, status: report.Skipped, category: "dry-run"},
+		{
+			name:    "up to date",
+			version: "v1.0.0",
+			existed: true,
+			err:     fmt.Errorf("%w: versions match", tools.ErrUpToDate),
+			status:  report.UpToDate,
+		},
+		{name: "dependency", err: tools.ErrDependency, status: report.Skipped, category: "dependency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool := &tools.Tool{Name: "tool", Previous: tt.previous}
+			tool.Version.Version = tt.version
+
+			status, category := commands.Outcome(tool, tt.existed, tt.err, tt.dry)
+			if status != tt.status || category != tt.category {
+				t.Errorf("Outcome() = %q, %q, want %q, %q", status, category, tt.status, tt.category)
+			}
+		})
+	}
+}
+
+func TestLegend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hint match.Hint
+		want string
+	}{
+		{name: "weight", hint: match.Hint{Pattern: "linux", Weight: "2"}, want: `"linux" (weight 2)`},
+		{name: "must", hint: match.Hint{Pattern: "amd64", Must: true}, want: `"amd64" (must)`},
+		{name: "must-not", hint: match.Hint{Pattern: "musl", MustNot: true}, want: `"musl" (must-not)`},
+		{name: "glob", hint: match.Hint{Pattern: "*.tar.gz", Weight: "1", Type: match.Glob}, want: `"*.tar.gz" (weight 1, glob)`},
+		{name: "regex", hint: match.Hint{Pattern: "gz$", Weight: "1", Type: match.Regex}, want: `"gz$" (weight 1)`},
+		{
+			name: "content",
+			hint: match.Hint{Pattern: "bin/tool", Content: true},
+			want: `"bin/tool" (must, content, checked after download)`,
+		},
+		{
+			name: "content must-not glob",
+			hint: match.Hint{Pattern: "*.so", MustNot: true, Content: true, Type: match.Glob},
+			want: `"*.so" (must-not, glob, content, checked after download)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := commands.Legend(tt.hint); got != tt.want {
+				t.Errorf("Legend() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
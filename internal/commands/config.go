@@ -3,9 +3,11 @@ package commands
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
+	"github.com/idelchi/godyl/internal/report"
 	"github.com/idelchi/godyl/internal/tools"
 	"github.com/idelchi/godyl/internal/tools/sources"
 	"github.com/idelchi/godyl/pkg/file"
@@ -84,6 +86,27 @@ type Config struct {
 	// Path to tools configuration file
 	Tools string
 
+	// Command to run instead of installing the tools, given as positional argument (e.g. "explain")
+	Command string `mapstructure:"-"`
+
+	// Arguments to the command
+	Args []string `mapstructure:"-"`
+
+	// Output format for commands (table, json)
+	Format string
+
+	// Time to wait for another godyl installing into the same output
+	LockTimeout time.Duration `mapstructure:"lock-timeout"`
+
+	// Format of the report of the outcome of each tool (json, junit, markdown)
+	Report report.Format
+
+	// Path to write the report to, required for the json and junit formats as the logs are written to stdout
+	ReportFile string `mapstructure:"report-file"`
+
+	// Install only the tools which failed in the last run
+	RetryFailed bool `mapstructure:"retry-failed"`
+
 	// Output path for the downloaded tools
 	Output string
 
@@ -102,6 +125,9 @@ type Config struct {
 	// Architecture to install the tools for
 	Arch string `mapstructure:"arch"`
 
+	// Time limit for installing each tool
+	Timeout time.Duration `mapstructure:"timeout"`
+
 	// Tokens for authentication
 	Tokens Tokens `mapstructure:",squash"`
 }
@@ -113,6 +139,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("%w: unknown update strategy: %q: allowed are %v", ErrUsage, c.Update.Strategy, allowedUpdateStrategies)
 	}
 
+	allowedFormats := []string{"table", "json"}
+	if !slices.Contains(allowedFormats, c.Format) {
+		return fmt.Errorf("%w: unknown format: %q: allowed are %v", ErrUsage, c.Format, allowedFormats)
+	}
+
+	if c.Report != "" && !slices.Contains(report.Formats(), c.Report) {
+		return fmt.Errorf("%w: unknown report format: %q: allowed are %v", ErrUsage, c.Report, report.Formats())
+	}
+
+	if c.Report != "" && c.Report != report.Markdown && c.ReportFile == "" {
+		return fmt.Errorf("%w: the %s report requires --report-file, as the logs are written to stdout", ErrUsage, c.Report)
+	}
+
+	if c.Timeout < 0 {
+		return fmt.Errorf("%w: timeout must not be negative, got %s", ErrUsage, c.Timeout)
+	}
+
 	if IsSet("config") && !c.Defaults.Exists() {
 		return fmt.Errorf("%w: defaults file %q does not exist", ErrUsage, c.Defaults)
 	}
@@ -66,6 +66,10 @@ func (d *Defaults) Merge(cfg Config) (err error) {
 		d.Source.Github.Token = cfg.Tokens.GitHub
 	}
 
+	if IsSet("timeout") {
+		d.Timeout.Total = cfg.Timeout
+	}
+
 	if IsSet("os") {
 		err = d.Platform.OS.Parse(cfg.OS)
 		d.Platform.Extension = d.Platform.Extension.Default(d.Platform.OS)
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/idelchi/godyl/internal/github"
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/tools/sources/common"
+)
+
+// Exit codes, per class of failure, allowing wrapper scripts to react differently.
+const (
+	// ExitOK indicates that all tools were processed successfully.
+	ExitOK = 0
+	// ExitError indicates an error not falling into any of the other classes, e.g. loading the tools.
+	ExitError = 1
+	// ExitUsage indicates invalid flags, arguments or configuration.
+	ExitUsage = 2
+	// ExitPartial indicates that some, but not all of the tools failed.
+	ExitPartial = 3
+	// ExitTotal indicates that all of the tools failed.
+	ExitTotal = 4
+	// ExitRateLimited indicates that tools failed due to the rate limits of the GitHub API.
+	ExitRateLimited = 5
+	// ExitVerification indicates that tools failed as the downloads did not match the platform or hints.
+	ExitVerification = 6
+	// ExitInterrupted indicates that the run was interrupted, following the shell convention for SIGINT.
+	ExitInterrupted = 130
+)
+
+var (
+	// ErrPartial indicates that some, but not all of the tools failed.
+	ErrPartial = errors.New("some tools failed to install")
+	// ErrTotal indicates that all of the tools failed.
+	ErrTotal = errors.New("all tools failed to install")
+	// ErrVerification indicates that a download did not match the platform or hints of the tool.
+	ErrVerification = errors.New("verification failed")
+)
+
+// ExitCode returns the exit code for the error returned by App.Run.
+// If tools failed for several reasons, rate limiting takes precedence over verification failures.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrUsage):
+		return ExitUsage
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.Is(err, github.ErrRateLimited):
+		return ExitRateLimited
+	case errors.Is(err, ErrVerification):
+		return ExitVerification
+	case errors.Is(err, ErrTotal):
+		return ExitTotal
+	case errors.Is(err, ErrPartial):
+		return ExitPartial
+	default:
+		return ExitError
+	}
+}
+
+// failure returns the error for the failed tools, classifying the run as a partial or total failure,
+// and marking whether any of the tools failed due to rate limiting or verification.
+func (app *App) failure() error {
+	failed := app.report.Count(report.Failed)
+
+	// Tools filtered by tags, or skipped as their dependencies failed, were not attempted.
+	attempted := 0
+
+	for _, entry := range app.report.Entries {
+		if entry.Status != report.Filtered && entry.Category != "dependency" {
+			attempted++
+		}
+	}
+
+	class := ErrPartial
+	if failed == attempted {
+		class = ErrTotal
+	}
+
+	err := fmt.Errorf("%w: %d of %d", class, failed, attempted)
+
+	for _, cause := range app.failures {
+		switch {
+		case errors.Is(cause, github.ErrRateLimited) && !errors.Is(err, github.ErrRateLimited):
+			err = fmt.Errorf("%w (%w)", err, github.ErrRateLimited)
+		case common.IsMismatch(cause) && !errors.Is(err, ErrVerification):
+			err = fmt.Errorf("%w (%w)", err, ErrVerification)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,294 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/internal/tools"
+	"github.com/idelchi/godyl/internal/tools/sources"
+	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/pretty"
+	"github.com/idelchi/godyl/pkg/utils"
+)
+
+// explanation is the output of the explain command.
+type explanation struct {
+	Tool   string `json:"tool"`
+	Source string `json:"source"`
+	Path   string `json:"path"`
+	Error  string `json:"error,omitempty"`
+
+	match.Explanation
+}
+
+// platformColumns maps the platform criteria to their column headers, in display order.
+var platformColumns = []struct {
+	criterion string
+	header    string
+}{
+	{"os", "OS="},
+	{"os compatible", "OS~"},
+	{"arch", "ARCH="},
+	{"arch compatible", "ARCH~"},
+	{"arch level", "LEVEL"},
+	{"arch emulated", "EMU"},
+	{"arch unknown", "ARCH?"},
+	{"library", "LIB="},
+	{"library compatible", "LIB~"},
+}
+
+// explain resolves a single tool and prints how each of its release assets was scored.
+func (app *App) explain(ctx context.Context, name string) error {
+	tool, err := app.findTool(name)
+	if err != nil {
+		return err
+	}
+
+	if err := tool.ApplyDefaults(app.defaults.Defaults); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrUsage, tool.Name, err)
+	}
+
+	// Resolve regardless of existing installations and tags, to always reach the asset selection.
+	tool.Strategy = tools.Force
+
+	resolveErr := tool.Resolve(ctx, tools.TagFilter{})
+
+	out := explanation{
+		Tool:   tool.Name,
+		Source: string(tool.Source.Type),
+		Path:   tool.Path,
+	}
+
+	if resolveErr != nil {
+		out.Error = resolveErr.Error()
+	}
+
+	if tool.Source.Type == sources.GITHUB {
+		out.Explanation = tool.Source.Github.Explanation()
+	}
+
+	if app.cfg.Format == "json" {
+		pretty.PrintJSON(out)
+	} else {
+		out.writeTable(os.Stdout)
+	}
+
+	if resolveErr != nil {
+		return fmt.Errorf("resolving %q: %w", tool.Name, resolveErr)
+	}
+
+	return nil
+}
+
+// findTool looks up a tool by name (or any of its executable names) in the tools file.
+// If the tools file does not exist or does not contain the tool, the name is treated as a simple tool.
+func (app *App) findTool(name string) (tools.Tool, error) {
+	if !file.File(app.cfg.Tools).Exists() && !utils.IsURL(app.cfg.Tools) {
+		return tools.Tool{Name: name}, nil
+	}
+
+	var toolsList tools.Tools
+	if err := toolsList.Load(app.cfg.Tools); err != nil {
+		return tools.Tool{}, fmt.Errorf("loading tools from %q: %w", app.cfg.Tools, err)
+	}
+
+	for _, tool := range toolsList {
+		if tool.Name == name || slices.Contains(tool.Exe.Names(), name) {
+			return tool, nil
+		}
+	}
+
+	return tools.Tool{Name: name}, nil
+}
+
+// writeTable writes the explanation as a table, followed by a legend for the hints.
+func (e explanation) writeTable(w io.Writer) {
+	fmt.Fprintf(w, "tool:     %s\n", e.Tool)
+	fmt.Fprintf(w, "source:   %s\n", e.Source)
+	fmt.Fprintf(w, "platform: %s/%s", e.Requirements.Platform.OS, e.Requirements.Platform.Architecture)
+
+	if !e.Requirements.Platform.Library.IsUnset() {
+		fmt.Fprintf(w, " (%s)", e.Requirements.Platform.Library)
+	}
+
+	fmt.Fprintln(w)
+
+	if len(e.Evaluations) == 0 {
+		fmt.Fprintf(w, "\nno release assets were evaluated, the path resolved to %q\n", e.Path)
+
+		if e.Error != "" {
+			fmt.Fprintf(w, "error: %s\n", e.Error)
+		}
+
+		return
+	}
+
+	// Only show the platform criteria that contributed to any of the assets.
+	var columns []string
+
+	for _, column := range platformColumns {
+		for _, evaluation := range e.Evaluations {
+			if contributed(evaluation.Platform, column.criterion) {
+				columns = append(columns, column.criterion)
+
+				break
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := []string{" ", "ASSET", "OS", "ARCH", "LIBRARY", "EXT"}
+
+	for _, criterion := range columns {
+		for _, column := range platformColumns {
+			if column.criterion == criterion {
+				header = append(header, column.header)
+			}
+		}
+	}
+
+	for i := range e.Requirements.Hints {
+		header = append(header, fmt.Sprintf("H%d", i+1))
+	}
+
+	header = append(header, "SCORE", "QUALIFIED", "REJECTIONS")
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, result := range e.Evaluations.Results().Sorted() {
+		evaluation := e.find(result.Asset.Name)
+
+		marker := " "
+		if evaluation.Asset.Name == e.Winner {
+			marker = "*"
+		}
+
+		platform := evaluation.Asset.Platform
+
+		row := []string{
+			marker,
+			evaluation.Asset.Name,
+			orDash(platform.OS.String()),
+			orDash(platform.Architecture.String()),
+			orDash(platform.Library.String()),
+			orDash(platform.Extension.String()),
+		}
+
+		for _, criterion := range columns {
+			row = append(row, score(evaluation.Platform, criterion))
+		}
+
+		for _, hint := range evaluation.Hints {
+			row = append(row, fmt.Sprintf("%d", hint.Score))
+		}
+
+		row = append(
+			row,
+			fmt.Sprintf("%d", evaluation.Score),
+			fmt.Sprintf("%t", evaluation.Qualified),
+			strings.Join(evaluation.Rejections, "; "),
+		)
+
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "legend:")
+	fmt.Fprintln(w, "  =  exact match, ~  compatible match")
+
+	for i, hint := range e.Requirements.Hints {
+		fmt.Fprintf(w, "  H%d %s\n", i+1, legend(hint))
+	}
+
+	fmt.Fprintln(w)
+
+	if e.Winner != "" {
+		fmt.Fprintf(w, "winner: %s\n", e.Winner)
+	}
+
+	if e.Error != "" {
+		fmt.Fprintf(w, "error: %s\n", firstLine(e.Error))
+	}
+}
+
+// legend describes the hint: its pattern, how it affects the selection, its syntax if not a regular expression,
+// and whether it is only checked against the contents of the downloaded asset, which it must (not) contain.
+func legend(hint match.Hint) string {
+	kind := []string{fmt.Sprintf("weight %s", hint.Weight)}
+
+	switch {
+	case hint.MustNot:
+		kind = []string{"must-not"}
+	case hint.Must, hint.Content:
+		kind = []string{"must"}
+	}
+
+	if hint.Type == match.Glob {
+		kind = append(kind, "glob")
+	}
+
+	if hint.Content {
+		kind = append(kind, "content, checked after download")
+	}
+
+	return fmt.Sprintf("%q (%s)", hint.Pattern, strings.Join(kind, ", "))
+}
+
+// find returns the evaluation of the asset with the given name.
+func (e explanation) find(name string) match.Evaluation {
+	for _, evaluation := range e.Evaluations {
+		if evaluation.Asset.Name == name {
+			return evaluation
+		}
+	}
+
+	return match.Evaluation{}
+}
+
+// contributed checks whether the criterion is present in the contributions.
+func contributed(contributions match.Contributions, criterion string) bool {
+	for _, contribution := range contributions {
+		if contribution.Criterion == criterion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// score returns the score of the criterion in the contributions, or "-" if it did not contribute.
+func score(contributions match.Contributions, criterion string) string {
+	for _, contribution := range contributions {
+		if contribution.Criterion == criterion {
+			return fmt.Sprintf("%+d", contribution.Score)
+		}
+	}
+
+	return "-"
+}
+
+// orDash returns the value, or "-" if it is empty.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// firstLine returns the first line of the error message, as the match errors embed the full results.
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+
+	return strings.TrimSuffix(strings.TrimSpace(line), ":")
+}
@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/tools"
+)
+
+// Unexported functions, exposed to the tests.
+var (
+	Legend = legend
+)
+
+// Outcome exposes the classification of a tool's result to the tests.
+func Outcome(tool *tools.Tool, existed bool, err error, dry bool) (report.Status, string) {
+	return outcome(result{tool: tool, existed: existed, err: err}, dry)
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/pflag"
@@ -45,20 +47,35 @@ func flags() {
 	pflag.String("log", string(logger.INFO), "Log level (debug, info, warn, error, silent)")
 	pflag.IntP("parallel", "j", runtime.NumCPU(), "Number of parallel downloads. 0 means unlimited.")
 	pflag.BoolP("no-verify-ssl", "k", false, "Skip SSL verification")
+	pflag.String("format", "table", "Output format for commands (table, json)")
+	pflag.Duration("lock-timeout", 5*time.Minute,
+		"Time to wait for another godyl installing into the same output. Negative waits indefinitely.")
+	pflag.String("report", "", "Write a report of the outcome of each tool (json, junit, markdown)")
+	pflag.String("report-file", "", "Path to write the report to, required for json and junit")
+	pflag.Bool("retry-failed", false, "Install only the tools which failed in the last run, with the same inputs")
 
 	// Tool flags
 	pflag.String("output", "", "Output path for the downloaded tools")
-	pflag.StringSliceP("tags", "t", []string{"!native"}, "Tags to filter tools by. Prefix with '!' to exclude")
+	pflag.StringSliceP("tags", "t", []string{"!native"},
+		"Tags to filter tools by. Prefix with '!' to exclude, or combine with '&&', '||', '!' and parentheses")
 	pflag.String("source", string(sources.GITHUB), "Source from which to install the tools")
 	pflag.String("strategy", "none", "Strategy to use for updating tools")
 	pflag.String("github-token", "", "GitHub token for authentication")
 	pflag.String("os", "", "Operating system to install the tools for")
 	pflag.String("arch", "", "Architecture to install the tools for")
+	pflag.Duration("timeout", 0, "Time limit for installing each tool. 0 means no limit.")
 
 	pflag.CommandLine.SortFlags = false
 	pflag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [tools]\n\n", "godyl")
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [tools]\n", "godyl")
+		fmt.Fprintf(os.Stderr, "       %s [flags] explain <tool> [tools]\n", "godyl")
+		fmt.Fprintf(os.Stderr, "       %s [flags] use <tool>[@<version>] [tools]\n", "godyl")
+		fmt.Fprintf(os.Stderr, "       %s [flags] rollback <tool> [tools]\n\n", "godyl")
 		fmt.Fprintf(os.Stderr, "Tool manager that installs tools as specified in a YAML file.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  explain <tool>             Show how each release asset of the tool is scored\n")
+		fmt.Fprintf(os.Stderr, "  use <tool>[@<version>]     Switch to an installed version of the tool, or list them\n")
+		fmt.Fprintf(os.Stderr, "  rollback <tool>            Restore the executables replaced by the last installation\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		pflag.PrintDefaults()
 	}
@@ -72,7 +89,7 @@ func parseFlags(version string, defaults []byte) (cfg Config, err error) {
 
 	// Parse the command-line flags with suggestions enabled
 	if err := flagexp.ParseWithSuggestions(os.Args[1:]); err != nil {
-		return cfg, fmt.Errorf("parsing flags: %w", err)
+		return cfg, fmt.Errorf("%w: parsing flags: %w", ErrUsage, err)
 	}
 
 	// Bind pflag flags to viper
@@ -112,13 +129,25 @@ func parseFlags(version string, defaults []byte) (cfg Config, err error) {
 }
 
 func validateInput(cfg *Config) error {
-	switch pflag.NArg() {
+	args := pflag.Args()
+
+	if len(args) > 0 && slices.Contains([]string{"explain", "use", "rollback"}, args[0]) {
+		if len(args) < 2 {
+			return fmt.Errorf("%w: %q requires a tool name", ErrUsage, args[0])
+		}
+
+		cfg.Command = args[0]
+		cfg.Args = args[1:2]
+		args = args[2:]
+	}
+
+	switch len(args) {
 	case 0:
 		cfg.Tools = "tools.yml"
 	case 1:
-		cfg.Tools = pflag.Arg(0)
+		cfg.Tools = args[0]
 	default:
-		return fmt.Errorf("too many arguments: %d", pflag.NArg())
+		return fmt.Errorf("%w: too many arguments: %d", ErrUsage, pflag.NArg())
 	}
 
 	return nil
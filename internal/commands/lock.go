@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/idelchi/godyl/internal/manifest"
+	"github.com/idelchi/godyl/pkg/lock"
+	"github.com/idelchi/godyl/pkg/logger"
+)
+
+// outputLocks holds the locks on the output folders, each acquired once for the whole run.
+type outputLocks struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	log     *logger.Logger
+	held    map[string]*outputLock
+}
+
+// outputLock is the lock on a single output folder, guarded on its own
+// so that waiting for one folder does not block the others.
+type outputLock struct {
+	mu   sync.Mutex
+	lock *lock.Lock
+}
+
+// LockAll locks the output folders in sorted order, waiting up to the timeout for each,
+// so that processes locking the same folders cannot deadlock.
+func (l *outputLocks) LockAll(ctx context.Context, outputs []string) error {
+	outputs = slices.Compact(slices.Sorted(slices.Values(outputs)))
+
+	for _, output := range outputs {
+		if err := l.lock(ctx, output, l.timeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lock locks the output folder against other godyl processes, unless this process already holds the lock.
+// Folders not locked up front are locked without waiting, as waiting for them out of order could deadlock.
+func (l *outputLocks) Lock(ctx context.Context, output string) error {
+	return l.lock(ctx, output, 0)
+}
+
+// lock locks the output folder, waiting up to the timeout, unless this process already holds the lock.
+func (l *outputLocks) lock(ctx context.Context, output string, timeout time.Duration) error {
+	l.mu.Lock()
+
+	if l.held == nil {
+		l.held = make(map[string]*outputLock)
+	}
+
+	held, ok := l.held[output]
+	if !ok {
+		held = &outputLock{}
+		l.held[output] = held
+	}
+
+	l.mu.Unlock()
+
+	held.mu.Lock()
+	defer held.mu.Unlock()
+
+	if held.lock != nil {
+		return nil
+	}
+
+	acquired, err := lockOutput(ctx, output, timeout, l.log)
+	if err != nil {
+		return err
+	}
+
+	held.lock = acquired
+
+	return nil
+}
+
+// Release releases all held locks.
+func (l *outputLocks) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for output, held := range l.held {
+		held.mu.Lock()
+
+		if held.lock != nil {
+			if err := held.lock.Release(); err != nil {
+				l.log.Warn("releasing lock on %q: %v", output, err)
+			}
+		}
+
+		held.mu.Unlock()
+	}
+
+	l.held = nil
+}
+
+// lockOutputs locks the output folders of the scheduled tools up front, unless only simulating the installation.
+// Tools whose folder cannot be determined yet fail when resolving, or lock their folder when installing, without waiting.
+func (tp *ToolProcessor) lockOutputs(ctx context.Context, nodes []*node) error {
+	if tp.app.cfg.Dry {
+		return nil
+	}
+
+	var outputs []string
+
+	for _, n := range nodes {
+		if output, err := n.tool.OutputFolder(); err == nil {
+			outputs = append(outputs, output)
+		}
+	}
+
+	return tp.locks.LockAll(ctx, outputs)
+}
+
+// lockOutput locks the output folder, waiting up to the timeout for another godyl process to finish.
+func lockOutput(ctx context.Context, output string, timeout time.Duration, log *logger.Logger) (*lock.Lock, error) {
+	path := filepath.Join(output, manifest.Folder, "lock")
+
+	held, err := lock.Acquire(ctx, path, 0)
+	if errors.Is(err, lock.ErrLocked) && timeout != 0 {
+		log.Info("waiting for another godyl installing into %q (pid %d)", output, lock.Holder(path))
+
+		held, err = lock.Acquire(ctx, path, timeout)
+	}
+
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, fmt.Errorf("another godyl is installing into %q (pid %d): %w", output, lock.Holder(path), err)
+	}
+
+	return held, err
+}
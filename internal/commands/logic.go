@@ -1,13 +1,19 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/runstate"
 	"github.com/idelchi/godyl/internal/tools"
 	"github.com/idelchi/godyl/internal/tools/sources/common"
 	"github.com/idelchi/godyl/pkg/file"
@@ -34,8 +40,23 @@ type App struct {
 	toolsList       tools.Tools
 	hasInstallError bool // Indicates if any tool failed to install
 
+	// report records the outcome of each tool, with reported mapping the tools to their entries.
+	report   *report.Report
+	reported map[*tools.Tool]int
+
+	// failures holds the errors of the failed tools, to classify the failure of the run.
+	failures []error
+
+	// indices maps the tools to their position in the tools file.
+	indices map[*tools.Tool]int
+	// retry holds the state of the last run, with the tools which failed in it, when retrying them.
+	retry *runstate.State
+
 	collectedTools []tools.Tool
 
+	// chooser resolves ambiguous asset matches interactively, nil if not running in a terminal.
+	chooser *chooser
+
 	version string
 
 	embedded embedded
@@ -47,6 +68,14 @@ type result struct {
 	found file.File
 	err   error
 	msg   string
+	// aborted marks a tool that was interrupted before completing.
+	aborted bool
+	// phase names the step the result stems from (resolve, install, post).
+	phase string
+	// existed indicates that the tool was already installed before.
+	existed bool
+	// elapsed is the time spent on the tool so far.
+	elapsed time.Duration
 }
 
 // NewApp initializes a new App instance.
@@ -61,7 +90,8 @@ func NewApp(version string, defaults, tools []byte) *App {
 }
 
 // Run is the main entry point of the application.
-func (app *App) Run() error {
+// Cancelling the context, e.g. on an interrupt, aborts the tools still being installed.
+func (app *App) Run(ctx context.Context) error {
 	if err := app.initialize(); err != nil {
 		return err
 	}
@@ -73,25 +103,45 @@ func (app *App) Run() error {
 	}
 
 	if app.cfg.Update.Update {
-		return app.processUpdate()
+		return app.processUpdate(ctx)
 	}
 
 	app.log = logger.New(app.cfg.Log)
 
+	switch app.cfg.Command {
+	case "explain":
+		return app.explain(ctx, app.cfg.Args[0])
+	case "use":
+		return app.use(ctx, app.cfg.Args[0])
+	case "rollback":
+		return app.rollback(ctx, app.cfg.Args[0])
+	}
+
+	if app.cfg.RetryFailed {
+		if retry, err := app.loadRetry(); err != nil || !retry {
+			return err
+		}
+	}
+
 	app.logStartupInfo()
 
 	if err := app.loadToolsList(); err != nil {
 		return err
 	}
 
-	tags, withoutTags := splitTags(app.cfg.Tags)
+	app.selectRetries()
 
-	if err := app.processTools(tags, withoutTags); err != nil {
+	tags, err := tools.ParseTagFilter(app.cfg.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+
+	if err := app.processTools(ctx, tags); err != nil {
 		return err
 	}
 
 	if app.hasInstallError {
-		return errors.New("one or more tools failed to install")
+		return app.failure()
 	}
 
 	return nil
@@ -101,12 +151,12 @@ func (app *App) Run() error {
 func (app *App) initialize() error {
 	cfg, err := parseFlags(app.version, app.embedded.defaults)
 	if err != nil {
-		return fmt.Errorf("error parsing flags: %v", err)
+		return fmt.Errorf("error parsing flags: %w", err)
 	}
 	app.cfg = cfg
 
 	if err := app.cfg.Validate(); err != nil {
-		return fmt.Errorf("error validating configuration: %v", err)
+		return fmt.Errorf("error validating configuration: %w", err)
 	}
 
 	app.defaults = Defaults{}
@@ -121,14 +171,14 @@ func (app *App) initialize() error {
 }
 
 // processUpdate handles the update process based on the configuration.
-func (app *App) processUpdate() error {
+func (app *App) processUpdate(ctx context.Context) error {
 	updater := GodylUpdater{
 		Strategy:    app.cfg.Update.Strategy,
 		Defaults:    app.defaults.Defaults,
 		NoVerifySSL: app.cfg.NoVerifySSL,
 	}
 
-	if err := updater.Update(app.version); err != nil {
+	if err := updater.Update(ctx, app.version); err != nil {
 		return fmt.Errorf("error updating: %v", err)
 	}
 
@@ -169,9 +219,37 @@ func (app *App) loadTools(path string) (tools.Tools, error) {
 }
 
 // processTools processes each tool in the tools list concurrently.
-func (app *App) processTools(tags, withoutTags []string) error {
+func (app *App) processTools(ctx context.Context, tags tools.TagFilter) error {
+	app.chooser = newChooser(app.canSaveTools())
+	app.report = report.New()
+	app.reported = make(map[*tools.Tool]int)
+	app.indices = make(map[*tools.Tool]int)
+
 	processor := NewToolProcessor(app)
-	return processor.Process(tags, withoutTags)
+	err := processor.Process(ctx, tags)
+
+	if !app.cfg.Dry {
+		app.saveRunState()
+	}
+
+	if reportErr := app.writeReport(); reportErr != nil {
+		err = errors.Join(err, reportErr)
+	}
+
+	if saved, saveErr := app.chooser.Save(); saveErr != nil {
+		app.log.Error("saving choices: %v", saveErr)
+	} else if len(saved) > 0 {
+		app.log.Info("saved %d choice(s) to %v", len(app.chooser.hints), saved)
+	}
+
+	return err
+}
+
+// canSaveTools checks whether the tools were loaded from a local YAML file that can be written back to.
+func (app *App) canSaveTools() bool {
+	path := app.cfg.Tools
+
+	return (strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml")) && file.File(path).Exists()
 }
 
 // ToolProcessor handles concurrent processing of tools.
@@ -181,30 +259,58 @@ type ToolProcessor struct {
 	errGroup  *errgroup.Group
 	waitGroup *sync.WaitGroup
 	toolChan  chan tools.Tool
+
+	// completions holds the newly created completion folders, per shell.
+	completions   map[tools.Role]string
+	completionsMu sync.Mutex
+
+	// locks holds the locks on the output folders installed into.
+	locks outputLocks
+
+	// nodes maps the tools to their place in the schedule.
+	nodes map[*tools.Tool]*node
+	// slots limits the number of tools processed in parallel, unlimited if nil.
+	slots chan struct{}
 }
 
 // NewToolProcessor creates a new ToolProcessor.
 func NewToolProcessor(app *App) *ToolProcessor {
 	return &ToolProcessor{
-		app:      app,
-		resultCh: make(chan result),
-		errGroup: &errgroup.Group{},
-		toolChan: make(chan tools.Tool),
+		app:         app,
+		resultCh:    make(chan result),
+		errGroup:    &errgroup.Group{},
+		toolChan:    make(chan tools.Tool),
+		completions: make(map[tools.Role]string),
+		locks:       outputLocks{timeout: app.cfg.LockTimeout, log: app.log},
+		nodes:       make(map[*tools.Tool]*node),
 	}
 }
 
 // Process starts processing tools with the given tags.
-func (tp *ToolProcessor) Process(tags, withoutTags []string) error {
+// Tools are processed concurrently, each once the tools it depends on are done.
+// Once the context is cancelled, the remaining tools are aborted and a summary is logged.
+func (tp *ToolProcessor) Process(ctx context.Context, tags tools.TagFilter) error {
+	nodes, err := tp.schedule()
+	if err != nil {
+		return err
+	}
+
 	tp.setupConcurrencyLimit()
 
+	defer tp.locks.Release()
+
+	if err := tp.lockOutputs(ctx, nodes); err != nil {
+		return err
+	}
+
 	tp.waitGroup = &sync.WaitGroup{}
 	tp.waitGroup.Add(1)
 
 	go tp.collectResults()
 
-	for _, tool := range tp.app.toolsList {
+	for _, n := range nodes {
 		tp.errGroup.Go(func() error {
-			return tp.processTool(&tool, tags, withoutTags)
+			return tp.run(ctx, n, tags)
 		})
 	}
 
@@ -215,17 +321,30 @@ func (tp *ToolProcessor) Process(tags, withoutTags []string) error {
 	close(tp.resultCh)
 	tp.waitGroup.Wait()
 
+	tp.app.logCompletionSnippet(tp.completions)
+	tp.app.logSummary()
+
+	if ctx.Err() != nil {
+		tp.app.log.Info("")
+		completed, aborted := tp.app.processed()
+
+		tp.app.log.Always("interrupted: completed: %d, aborted: %d", completed, aborted)
+
+		return fmt.Errorf("installation interrupted: %w", context.Cause(ctx))
+	}
+
 	if tp.app.hasInstallError {
-		return errors.New("one or more tools failed to install")
+		return tp.app.failure()
 	}
 
 	return nil
 }
 
 // setupConcurrencyLimit sets the concurrency limit if specified in the config.
+// Tools waiting for their dependencies do not count towards the limit.
 func (tp *ToolProcessor) setupConcurrencyLimit() {
 	if tp.app.cfg.Parallel > 0 {
-		tp.errGroup.SetLimit(tp.app.cfg.Parallel)
+		tp.slots = make(chan struct{}, tp.app.cfg.Parallel)
 		tp.app.log.Info("running with %d parallel downloads", tp.app.cfg.Parallel)
 	}
 }
@@ -240,38 +359,114 @@ func (tp *ToolProcessor) collectResults() {
 	}
 }
 
-// processTool processes an individual tool.
-func (tp *ToolProcessor) processTool(tool *tools.Tool, tags, withoutTags []string) error {
-	tool.ApplyDefaults(tp.app.defaults.Defaults)
+// processTool processes an individual tool, within its total time limit.
+// Tools not yet started when the context is cancelled are aborted right away.
+func (tp *ToolProcessor) processTool(ctx context.Context, tool *tools.Tool, tags tools.TagFilter) error {
+	if ctx.Err() != nil {
+		tp.send(ctx, result{tool: tool, err: context.Cause(ctx)})
+
+		return nil
+	}
 
-	if err := tool.Resolve(tags, withoutTags); err != nil {
-		tp.resultCh <- result{tool: tool, err: err}
+	return common.WithTimeout(ctx, "total", tool.Timeout.Total, func(ctx context.Context) error {
+		tp.install(ctx, tool, tags)
 
 		return nil
+	})
+}
+
+// install resolves, downloads and installs the tool, sending the results of each step.
+func (tp *ToolProcessor) install(ctx context.Context, tool *tools.Tool, tags tools.TagFilter) {
+	start := time.Now()
+
+	send := func(res result) {
+		res.elapsed = time.Since(start)
+
+		tp.send(ctx, res)
+	}
+
+	if err := tool.Resolve(ctx, tags); err != nil {
+		send(result{tool: tool, err: err, phase: "resolve"})
+
+		return
 	}
 
 	if tp.app.cfg.Dry {
-		tp.resultCh <- result{tool: tool, err: nil}
-		return nil
+		send(result{tool: tool, err: nil})
+		return
 	}
 
 	if tp.app.cfg.NoVerifySSL {
 		tool.NoVerifySSL = true
 	}
 
-	msg, found, err := tool.Download()
-	tp.resultCh <- result{tool: tool, found: found, err: err, msg: msg}
+	tool.LockTimeout = tp.app.cfg.LockTimeout
+
+	if err := tp.locks.Lock(ctx, tool.Output); err != nil {
+		send(result{tool: tool, err: err, phase: "install"})
+
+		return
+	}
+
+	existed := tool.Exists()
+
+	msg, found, err := tool.Download(ctx)
+	send(result{tool: tool, found: found, err: err, msg: msg, phase: "install", existed: existed})
 
 	if err != nil {
-		return nil
+		return
 	}
 
-	output, _, err := tool.Post.Install(common.InstallData{Env: tool.Env})
+	var output string
+
+	err = common.WithTimeout(ctx, "post", tool.Timeout.Post, func(ctx context.Context) (err error) {
+		output, _, err = tool.Post.Install(ctx, common.InstallData{Env: tool.Env})
+
+		return err
+	})
 	if err != nil {
-		tp.resultCh <- result{tool: tool, err: err, msg: output}
+		send(result{tool: tool, err: err, msg: output, phase: "post", existed: existed})
+
+		return
 	}
 
-	return nil
+	var created map[tools.Role]string
+
+	err = common.WithTimeout(ctx, "post", tool.Timeout.Post, func(ctx context.Context) (err error) {
+		created, err = tool.GenerateCompletions(ctx)
+
+		return err
+	})
+
+	tp.completionsMu.Lock()
+	maps.Copy(tp.completions, created)
+	tp.completionsMu.Unlock()
+
+	if err != nil {
+		send(result{tool: tool, err: err, phase: "post", existed: existed})
+	}
+}
+
+// send passes the result on for logging.
+// Failures caused by the expired time limit of the tool are marked as timed out,
+// and those caused by the cancelled context as aborted.
+// Failing tools are marked as such, to skip the tools depending on them.
+func (tp *ToolProcessor) send(ctx context.Context, res result) {
+	switch {
+	case res.err == nil:
+	case errors.Is(context.Cause(ctx), tools.ErrTimeout):
+		if !errors.Is(res.err, tools.ErrTimeout) {
+			res.err = fmt.Errorf("%w: %v", context.Cause(ctx), res.err)
+		}
+	case ctx.Err() != nil || errors.Is(res.err, context.Canceled):
+		res.aborted = true
+	}
+
+	if n, ok := tp.nodes[res.tool]; ok && failing(res) {
+		n.failed.Store(true)
+	}
+
+	tp.resultCh <- res
 }
 
 // processResult processes the result from a tool operation.
@@ -286,6 +481,15 @@ func (app *App) processResult(res result) {
 	app.log.Debug("-------")
 	app.log.Debug(pretty.YAMLMasked(tool))
 	app.log.Debug("-------")
+
+	app.record(res)
+
+	if res.aborted {
+		app.log.Warn("  aborted: %v", err)
+
+		return
+	}
+
 	if err != nil {
 		app.handleToolError(tool, err, msg)
 	} else {
@@ -293,16 +497,32 @@ func (app *App) processResult(res result) {
 	}
 }
 
+// processed counts the tools processed before and after an interruption.
+// Each tool is counted once, by its last result, as recorded in the report.
+func (app *App) processed() (completed, aborted int) {
+	for _, entry := range app.report.Entries {
+		if entry.Category == "aborted" {
+			aborted++
+		} else {
+			completed++
+		}
+	}
+
+	return completed, aborted
+}
+
 // handleToolError logs errors encountered during tool processing.
 func (app *App) handleToolError(tool *tools.Tool, err error, msg string) {
-	if errors.Is(err, tools.ErrAlreadyExists) ||
-		errors.Is(err, tools.ErrUpToDate) ||
-		errors.Is(err, tools.ErrDoesNotHaveTags) ||
-		errors.Is(err, tools.ErrDoesHaveTags) ||
-		errors.Is(err, tools.ErrSkipped) {
+	if skipping(err) {
 		app.log.Warn("  %v", err)
+	} else if errors.Is(err, tools.ErrTimeout) {
+		app.hasInstallError = true
+		app.failures = append(app.failures, err)
+		app.log.Error("  timed out")
+		app.log.Error("  %v", err)
 	} else {
 		app.hasInstallError = true // Set the flag if a tool fails to install
+		app.failures = append(app.failures, err)
 		app.log.Error("  failed to install")
 		app.log.Debug("configuration:")
 		app.log.Debug("-------")
@@ -319,12 +539,68 @@ func (app *App) logToolSuccess(tool *tools.Tool, found file.File) {
 		app.log.Info("  version: %s", tool.Version.Version)
 	}
 	app.log.Info("  picked download %q", filepath.Base(tool.Path))
+	if tool.Emulated {
+		app.log.Warn("  emulated install: no native %s/%s build was found", tool.Platform.OS, tool.Platform.Architecture)
+	}
+	if len(tool.Completions) > 0 && !tool.Runnable() {
+		app.log.Warn("  completions skipped: %s/%s executables cannot run on this host", tool.Platform.OS, tool.Platform.Architecture)
+	}
 	if tool.Mode == "find" {
 		app.log.Info("  picked file %q", found)
 		app.log.Info("  installed successfully at %q", filepath.Join(tool.Output, tool.Exe.Name))
+		if tool.Versions.Enabled {
+			app.log.Info("  active version: %s", tool.Version.Version)
+
+			for _, version := range tool.Pruned {
+				app.log.Info("  removed old version %s", version)
+			}
+		}
 		app.logToolAliases(tool)
+
+		for _, extra := range tool.Exe.Extra {
+			app.log.Info("  installed %q at %q", extra.Name, filepath.Join(tool.Output, extra.Name))
+
+			for _, alias := range extra.Aliases {
+				app.log.Info("    - %q", filepath.Join(tool.Output, alias))
+			}
+		}
 	} else {
-		app.log.Info("  extracted to %q", tool.Output)
+		app.log.Info("  extracted to %q", filepath.Join(tool.Output, tool.Extract.Target))
+	}
+}
+
+// logCompletionSnippet logs how to load the completions from the newly created completion folders.
+// As the folders are only created once, the snippet is shown only on the first installation of completions.
+func (app *App) logCompletionSnippet(created map[tools.Role]string) {
+	if len(created) == 0 {
+		return
+	}
+
+	snippets := map[tools.Role]string{
+		tools.Bash:       `for f in %q/*; do source "$f"; done`,
+		tools.Zsh:        `fpath=(%q $fpath); autoload -Uz compinit && compinit`,
+		tools.Fish:       `set -p fish_complete_path %q`,
+		tools.Powershell: `Get-ChildItem %q -Filter *.ps1 | ForEach-Object { . $_.FullName }`,
+	}
+
+	profiles := map[tools.Role]string{
+		tools.Bash:       "~/.bashrc",
+		tools.Zsh:        "~/.zshrc",
+		tools.Fish:       "~/.config/fish/config.fish",
+		tools.Powershell: "$PROFILE",
+	}
+
+	app.log.Info("")
+	app.log.Always("shell completions were installed, to load them add the following to your shell configuration:")
+
+	for _, shell := range tools.Shells() {
+		dir, ok := created[shell]
+		if !ok {
+			continue
+		}
+
+		app.log.Always("  %s (%s):", shell, profiles[shell])
+		app.log.Always("    "+snippets[shell], dir)
 	}
 }
 
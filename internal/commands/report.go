@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/tools"
+)
+
+// record adds the outcome of the tool to the report.
+// A later result of the same tool, e.g. a failing post command after the installation, replaces the earlier one.
+func (app *App) record(res result) {
+	status, category := outcome(res, app.cfg.Dry)
+
+	entry := report.Entry{
+		Tool:          res.tool.Name,
+		Status:        status,
+		VersionBefore: res.tool.Previous,
+		VersionAfter:  res.tool.Version.Version,
+		Asset:         res.tool.Path,
+		Duration:      res.elapsed,
+		Category:      category,
+	}
+
+	if res.err != nil {
+		entry.Error = res.err.Error()
+	}
+
+	if i, ok := app.reported[res.tool]; ok {
+		app.report.Entries[i] = entry
+
+		return
+	}
+
+	app.reported[res.tool] = len(app.report.Entries)
+	app.report.Entries = append(app.report.Entries, entry)
+}
+
+// outcome classifies the result into the status of the tool, and the category of the reason for it.
+func outcome(res result, dry bool) (report.Status, string) {
+	err := res.err
+
+	switch {
+	case res.aborted:
+		return report.Failed, "aborted"
+	case err == nil && dry:
+		return report.Skipped, "dry-run"
+	case err == nil && res.existed && res.tool.Previous != "" && !tools.SameVersion(res.tool.Previous, res.tool.Version.Version):
+		return report.Upgraded, ""
+	case err == nil && res.existed:
+		return report.Reinstalled, ""
+	case err == nil:
+		return report.Installed, ""
+	case errors.Is(err, tools.ErrUpToDate):
+		return report.UpToDate, ""
+	case errors.Is(err, tools.ErrAlreadyExists):
+		return report.Skipped, "exists"
+	case errors.Is(err, tools.ErrSkipped):
+		return report.Skipped, "condition"
+	case errors.Is(err, tools.ErrDependency):
+		return report.Skipped, "dependency"
+	case errors.Is(err, tools.ErrDoesHaveTags), errors.Is(err, tools.ErrDoesNotHaveTags):
+		return report.Filtered, "tags"
+	case errors.Is(err, tools.ErrTimeout):
+		return report.Failed, "timeout"
+	default:
+		return report.Failed, res.phase
+	}
+}
+
+// writeReport writes the report of the run in the requested format, to the report file,
+// or to stdout after the logs for the Markdown format.
+func (app *App) writeReport() error {
+	format := app.cfg.Report
+	if format == "" {
+		if app.cfg.ReportFile == "" {
+			return nil
+		}
+
+		format = report.JSON
+	}
+
+	var w io.Writer = os.Stdout
+
+	if app.cfg.ReportFile != "" {
+		f, err := os.Create(app.cfg.ReportFile)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	if err := app.report.Write(w, format); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	if app.cfg.ReportFile != "" {
+		app.log.Info("wrote %s report to %q", format, app.cfg.ReportFile)
+	}
+
+	return nil
+}
+
+// logSummary logs the tools grouped by their outcome, with the one-line error of each failed tool.
+// Filtered tools are only counted, as they are usually the majority.
+func (app *App) logSummary() {
+	if len(app.report.Entries) == 0 {
+		return
+	}
+
+	app.log.Info("")
+	app.log.Always("summary:")
+
+	for _, status := range []report.Status{
+		report.Installed, report.Upgraded, report.Reinstalled, report.UpToDate,
+		report.Skipped, report.Filtered, report.Failed,
+	} {
+		var names []string
+
+		for _, entry := range app.report.Entries {
+			if entry.Status == status {
+				names = append(names, entry.Tool)
+			}
+		}
+
+		switch {
+		case len(names) == 0:
+		case status == report.Filtered:
+			app.log.Always("  %s: %d", status, len(names))
+		case status == report.Failed:
+			app.log.Always("  %s: %d", status, len(names))
+
+			for _, entry := range app.report.Entries {
+				if entry.Status == status {
+					message, _, _ := strings.Cut(entry.Error, "\n")
+					app.log.Always("    - %s: %s", entry.Tool, message)
+				}
+			}
+		default:
+			app.log.Always("  %s: %d (%s)", status, len(names), strings.Join(names, ", "))
+		}
+	}
+}
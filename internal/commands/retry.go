@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/idelchi/godyl/internal/report"
+	"github.com/idelchi/godyl/internal/runstate"
+	"github.com/idelchi/godyl/internal/tools"
+	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/utils"
+)
+
+// loadRetry replaces the tools file, tags, platform and output with those of the last run,
+// and selects the tools which failed in it. It returns false if no tools failed.
+func (app *App) loadRetry() (bool, error) {
+	state, err := runstate.Load()
+	if errors.Is(err, runstate.ErrNoState) {
+		return false, fmt.Errorf("%w: %w to retry", ErrUsage, err)
+	} else if err != nil {
+		return false, fmt.Errorf("loading the last run: %w", err)
+	}
+
+	if len(state.Failed) == 0 {
+		app.log.Info("no tools failed in the last run at %s", state.Time.Format("2006-01-02 15:04:05"))
+
+		return false, nil
+	}
+
+	if state.Tools == "-" {
+		return false, fmt.Errorf("%w: tools read from stdin cannot be retried", ErrUsage)
+	}
+
+	// Relative paths resolve the same as in the last run, without changing the working folder of this one.
+	app.cfg.Tools = state.Tools
+	if resolved := state.Resolve(state.Tools); !utils.IsURL(state.Tools) && file.File(resolved).IsFile() {
+		app.cfg.Tools = resolved
+	}
+
+	app.cfg.Tags = state.Tags
+	app.defaults.Output = state.Resolve(state.Output)
+	app.defaults.Platform = state.Platform
+
+	app.retry = &state
+
+	app.log.Info("retrying %d tool(s) which failed in the last run", len(state.Failed))
+
+	return true, nil
+}
+
+// selectRetries warns about the failed tools which are no longer found at the same position in the tools file.
+func (app *App) selectRetries() {
+	if app.retry == nil {
+		return
+	}
+
+	names := make([]string, len(app.toolsList))
+	for i, tool := range app.toolsList {
+		names[i] = tool.Name
+	}
+
+	for _, failed := range app.retry.Missing(names) {
+		app.log.Warn("%q is no longer found in %q, skipping it", failed.Name, app.cfg.Tools)
+	}
+}
+
+// retrying checks whether the tool at the index in the tools file is to be processed.
+// When retrying, only the failed tools are, keeping the version resolved and the working folder of the last run.
+func (app *App) retrying(index int, tool *tools.Tool) bool {
+	if app.retry == nil {
+		return true
+	}
+
+	failed, ok := app.retry.Retry(index, tool.Name)
+	if !ok {
+		return false
+	}
+
+	utils.SetIfEmpty(&tool.Version.Version, failed.Version)
+
+	tool.Dir = app.retry.Dir
+
+	return true
+}
+
+// saveRunState saves the inputs of the run and the tools which failed, for `--retry-failed`.
+// Tools skipped as a tool they depend on failed are saved as well, to be retried along with it.
+func (app *App) saveRunState() {
+	state := runstate.State{
+		Tools:    app.cfg.Tools,
+		Tags:     app.cfg.Tags,
+		Output:   app.defaults.Output,
+		Platform: app.defaults.Platform,
+		Failed:   []runstate.Tool{},
+	}
+
+	// Retrying keeps the working folder of the original run, against which the tools' relative paths resolve.
+	if app.retry != nil {
+		state.Dir = app.retry.Dir
+	}
+
+	for tool, i := range app.reported {
+		entry := app.report.Entries[i]
+		if entry.Status != report.Failed && entry.Category != "dependency" {
+			continue
+		}
+
+		state.Failed = append(state.Failed, runstate.Tool{
+			Index:   app.indices[tool],
+			Name:    entry.Tool,
+			Version: entry.VersionAfter,
+			Error:   entry.Error,
+		})
+	}
+
+	slices.SortFunc(state.Failed, func(a, b runstate.Tool) int {
+		return a.Index - b.Index
+	})
+
+	if err := state.Save(); err != nil {
+		app.log.Warn("saving the state of the run: %v", err)
+	}
+}
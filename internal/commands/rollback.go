@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/idelchi/godyl/internal/backup"
+)
+
+// rollback restores the executables of the tool, including their aliases, as they were before the last installation.
+func (app *App) rollback(ctx context.Context, name string) error {
+	tool, err := app.installedTool(name)
+	if err != nil {
+		return err
+	}
+
+	held, err := lockOutput(ctx, tool.Output, app.cfg.LockTimeout, app.log)
+	if err != nil {
+		return err
+	}
+	defer held.Release()
+
+	restored, err := backup.Rollback(tool.Output, tool.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, executable := range restored.Executables {
+		app.log.Info("restored %q as of %s", executable.Name, restored.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/idelchi/godyl/internal/tools"
+)
+
+// node is a tool scheduled once the tools it depends on are done.
+type node struct {
+	tool         *tools.Tool
+	dependencies []*node
+	// done is closed once the tool has been processed.
+	done chan struct{}
+	// failed marks a tool which failed, so that the tools depending on it are skipped.
+	failed atomic.Bool
+}
+
+// schedule creates the nodes of the tools to process, with the defaults applied, linked to the nodes of their dependencies.
+// Dependencies which are not processed, e.g. when retrying failed tools, are considered satisfied.
+func (tp *ToolProcessor) schedule() ([]*node, error) {
+	dependencies, err := tp.app.toolsList.Dependencies()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+
+	nodes := make(map[int]*node)
+	order := []*node{}
+
+	for i, tool := range tp.app.toolsList {
+		if !tp.app.retrying(i, &tool) {
+			continue
+		}
+
+		if err := tool.ApplyDefaults(tp.app.defaults.Defaults); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrUsage, tool.Name, err)
+		}
+
+		tool.Chooser = tp.app.chooser.For(&tool)
+
+		n := &node{tool: &tool, done: make(chan struct{})}
+
+		nodes[i] = n
+		order = append(order, n)
+		tp.nodes[n.tool] = n
+		tp.app.indices[n.tool] = i
+	}
+
+	for i, n := range nodes {
+		for _, dependency := range dependencies[i] {
+			if d, ok := nodes[dependency]; ok {
+				n.dependencies = append(n.dependencies, d)
+			}
+		}
+	}
+
+	return order, nil
+}
+
+// run processes the tool of the node once its dependencies are done, within the limit of parallel tools.
+// If a dependency failed, the tool is skipped and marked as failed itself, to skip its own dependents.
+func (tp *ToolProcessor) run(ctx context.Context, n *node, tags tools.TagFilter) error {
+	defer close(n.done)
+
+	for _, dependency := range n.dependencies {
+		<-dependency.done
+
+		// Once cancelled, the tool is reported as aborted instead.
+		if dependency.failed.Load() && ctx.Err() == nil {
+			n.failed.Store(true)
+			tp.send(ctx, result{
+				tool: n.tool,
+				err:  fmt.Errorf("%w: %q did not install", tools.ErrDependency, dependency.tool.Name),
+			})
+
+			return nil
+		}
+	}
+
+	if tp.slots != nil {
+		tp.slots <- struct{}{}
+		defer func() { <-tp.slots }()
+	}
+
+	return tp.processTool(ctx, n.tool, tags)
+}
+
+// failing checks whether the result fails the tool, as opposed to it being skipped or already installed.
+func failing(res result) bool {
+	return res.aborted || res.err != nil && !skipping(res.err)
+}
+
+// skipping checks whether the error indicates that the tool was not installed on purpose.
+func skipping(err error) bool {
+	return errors.Is(err, tools.ErrAlreadyExists) ||
+		errors.Is(err, tools.ErrUpToDate) ||
+		errors.Is(err, tools.ErrDoesNotHaveTags) ||
+		errors.Is(err, tools.ErrDoesHaveTags) ||
+		errors.Is(err, tools.ErrSkipped) ||
+		errors.Is(err, tools.ErrDependency)
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,7 +24,7 @@ type GodylUpdater struct {
 }
 
 // Update performs the update process for the godyl tool, applying the specified strategy.
-func (gu GodylUpdater) Update(version string) error {
+func (gu GodylUpdater) Update(ctx context.Context, version string) error {
 	// Set default strategy if none is provided.
 	if gu.Strategy == tools.None {
 		gu.Strategy = tools.Upgrade
@@ -47,8 +48,11 @@ func (gu GodylUpdater) Update(version string) error {
 	}
 
 	// Apply any default values to the tool.
-	tool.ApplyDefaults(gu.Defaults)
-	if err := tool.Resolve(nil, nil); err != nil {
+	if err := tool.ApplyDefaults(gu.Defaults); err != nil {
+		return fmt.Errorf("applying defaults: %w", err)
+	}
+
+	if err := tool.Resolve(ctx, tools.TagFilter{}); err != nil {
 		return fmt.Errorf("resolving tool: %w", err)
 	}
 
@@ -65,7 +69,7 @@ func (gu GodylUpdater) Update(version string) error {
 	fmt.Printf("Update requested from %q -> %q\n", version, tool.Version.Version)
 
 	// Download the tool.
-	output, err := gu.Get(tool)
+	output, err := gu.Get(ctx, tool)
 
 	defer func() {
 		folder := file.Folder(output)
@@ -102,7 +106,8 @@ func (gu GodylUpdater) Replace(path string) error {
 }
 
 // Get downloads the tool based on its source, placing it in a temporary directory, and returns the output path.
-func (gu GodylUpdater) Get(tool tools.Tool) (string, error) {
+// The temporary directory is removed if the download fails.
+func (gu GodylUpdater) Get(ctx context.Context, tool tools.Tool) (_ string, err error) {
 	// Create a temporary directory to store the downloaded tool.
 	var dir file.Folder
 	// For Windows, get the directory of the current executable.
@@ -122,13 +127,19 @@ func (gu GodylUpdater) Get(tool tools.Tool) (string, error) {
 
 	tool.Output = dir.Path()
 
+	defer func() {
+		if err != nil {
+			dir.Remove()
+		}
+	}()
+
 	// Resolve any dependencies or settings for the tool.
-	if err := tool.Resolve(nil, nil); err != nil {
+	if err := tool.Resolve(ctx, tools.TagFilter{}); err != nil {
 		return "", fmt.Errorf("resolving tool: %w", err)
 	}
 
 	// Download the tool and capture any messages or errors.
-	if output, msg, err := tool.Download(); err != nil {
+	if output, msg, err := tool.Download(ctx); err != nil {
 		return "", fmt.Errorf("downloading tool: %w: %s: %s", err, output, msg)
 	}
 
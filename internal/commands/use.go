@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/tools"
+	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/pretty"
+)
+
+// installed lists the versions of a tool installed side by side.
+type installed struct {
+	Tool     string   `json:"tool"`
+	Active   string   `json:"active"`
+	Versions []string `json:"versions"`
+}
+
+// use switches the tool, given as <tool>@<version>, to one of its versions installed side by side.
+// Without a version, the installed versions are listed.
+func (app *App) use(ctx context.Context, arg string) error {
+	name, version, _ := strings.Cut(arg, "@")
+
+	tool, err := app.installedTool(name)
+	if err != nil {
+		return err
+	}
+
+	versions := tool.InstalledVersions()
+
+	if version != "" {
+		held, err := lockOutput(ctx, tool.Output, app.cfg.LockTimeout, app.log)
+		if err != nil {
+			return err
+		}
+		defer held.Release()
+
+		if err := versions.Use(version); err != nil {
+			return err
+		}
+
+		app.log.Info("switched %q to version %q", tool.Name, version)
+
+		return nil
+	}
+
+	out := installed{Tool: tool.Name}
+
+	if out.Versions, err = versions.List(); err != nil {
+		return fmt.Errorf("listing versions of %q: %w", tool.Name, err)
+	}
+
+	if out.Active, err = versions.Active(); err != nil {
+		return fmt.Errorf("reading active version of %q: %w", tool.Name, err)
+	}
+
+	if app.cfg.Format == "json" {
+		pretty.PrintJSON(out)
+
+		return nil
+	}
+
+	if len(out.Versions) == 0 {
+		fmt.Fprintf(os.Stdout, "no versions of %q are installed in %q\n", tool.Name, versions.Dir())
+
+		return nil
+	}
+
+	for _, version := range out.Versions {
+		marker := " "
+		if version == out.Active {
+			marker = "*"
+		}
+
+		fmt.Fprintf(os.Stdout, "%s %s\n", marker, version)
+	}
+
+	return nil
+}
+
+// installedTool looks up the tool and resolves its output folder as when installing,
+// without resolving its source.
+func (app *App) installedTool(name string) (tools.Tool, error) {
+	tool, err := app.findTool(name)
+	if err != nil {
+		return tool, err
+	}
+
+	if err := tool.ApplyDefaults(app.defaults.Defaults); err != nil {
+		return tool, err
+	}
+
+	output := file.Folder(tool.Output)
+	if err := output.Expand(); err != nil {
+		return tool, err
+	}
+	tool.Output = output.Path()
+
+	return tool, tool.TemplateFirst()
+}
@@ -33,6 +33,13 @@ func (p *Platform) Detect() error {
 	// Set the default library based on the OS and distribution
 	library = library.Default(os, distro)
 
+	// Determine the glibc version, to avoid binaries requiring a newer one
+	if library.Type == "gnu" && os.Type == "linux" {
+		if version, err := platform.GlibcVersion(); err == nil {
+			library.Version = version
+		}
+	}
+
 	// Determine the architecture from the system's kernel architecture
 	if err := arch.Parse(info.KernelArch); err != nil {
 		return err
@@ -45,6 +52,11 @@ func (p *Platform) Detect() error {
 		}
 	}
 
+	// Determine the x86-64 microarchitecture level from the CPU features
+	if arch.Type == "amd64" {
+		arch.Version = platform.AMD64Level()
+	}
+
 	// Populate the Platform struct with the detected values
 	*p = Platform{
 		OS:           os,
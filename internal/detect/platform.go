@@ -27,9 +27,19 @@ func (p *Platform) Parse(name string) {
 func (p *Platform) Merge(other Platform) {
 	utils.SetIfEmpty(&p.OS, other.OS)
 	utils.SetIfEmpty(&p.Architecture.Type, other.Architecture.Type)
-	utils.SetIfEmpty(&p.Architecture.Version, other.Architecture.Version)
-	utils.SetIfEmpty(&p.Architecture.Raw, other.Architecture.Raw)
-	utils.SetIfEmpty(&p.Library, other.Library)
+	if p.Architecture.Type == other.Architecture.Type {
+		utils.SetIfEmpty(&p.Architecture.Version, other.Architecture.Version)
+		utils.SetIfEmpty(&p.Architecture.Raw, other.Architecture.Raw)
+	}
+	// The library version, such as the host's glibc, only applies to the same OS and architecture.
+	library := other.Library
+	if p.OS.Type != other.OS.Type || p.Architecture.Type != other.Architecture.Type {
+		library.Version = ""
+	}
+	utils.SetIfEmpty(&p.Library, library)
+	if p.Library.Type == library.Type {
+		utils.SetIfEmpty(&p.Library.Version, library.Version)
+	}
 	utils.SetIfEmpty(&p.Extension, other.Extension)
 	utils.SetIfEmpty(&p.Distribution, other.Distribution)
 }
@@ -41,6 +51,7 @@ func (p Platform) ToMap() map[string]any {
 	platformMap["ARCH"] = p.Architecture.Type
 	platformMap["ARCH_VERSION"] = p.Architecture.Version
 	platformMap["LIBRARY"] = p.Library.String()
+	platformMap["LIBRARY_VERSION"] = p.Library.Version
 	platformMap["EXTENSION"] = p.Extension.String()
 	platformMap["DISTRIBUTION"] = p.Distribution.String()
 
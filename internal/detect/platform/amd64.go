@@ -0,0 +1,41 @@
+package platform
+
+import (
+	"golang.org/x/sys/cpu"
+)
+
+// AMD64Level returns the x86-64 microarchitecture level (1-4) supported by the CPU, as defined by the x86-64 psABI.
+// It returns 0 if the CPU is not an x86 processor.
+//
+// Features not exposed by CPUID through golang.org/x/sys/cpu (e.g. LAHF, MOVBE, LZCNT, F16C) are not checked,
+// as they are present on all known processors implementing the remaining features of their level.
+func AMD64Level() int {
+	x86 := cpu.X86
+
+	if !x86.HasSSE2 {
+		return 0
+	}
+
+	levels := [][]bool{
+		// x86-64-v2
+		{x86.HasCX16, x86.HasPOPCNT, x86.HasSSE3, x86.HasSSSE3, x86.HasSSE41, x86.HasSSE42},
+		// x86-64-v3
+		{x86.HasAVX, x86.HasAVX2, x86.HasBMI1, x86.HasBMI2, x86.HasFMA, x86.HasOSXSAVE},
+		// x86-64-v4
+		{x86.HasAVX512F, x86.HasAVX512BW, x86.HasAVX512CD, x86.HasAVX512DQ, x86.HasAVX512VL},
+	}
+
+	level := 1
+
+	for _, features := range levels {
+		for _, supported := range features {
+			if !supported {
+				return level
+			}
+		}
+
+		level++
+	}
+
+	return level
+}
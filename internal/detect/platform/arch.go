@@ -17,11 +17,19 @@ type Architecture struct {
 	Is32BitUserLand bool
 }
 
+var (
+	// amd64Level matches the microarchitecture levels, e.g. "amd64v3", "x86_64_v2" or "x86-64-v4".
+	amd64Level = regexp.MustCompile(`(?:amd64|x86_64|x86-64|x64)[-_]?v([1-4])`)
+	// armVersion matches the version of arm architectures, e.g. "armv7".
+	armVersion = regexp.MustCompile(`armv(\d+)`)
+)
+
 // ArchInfo holds information about an architecture type, including aliases and a parse function.
+// The parse function receives the matched alias and the full name, and returns the architecture version.
 type ArchInfo struct {
 	Type    string
 	Aliases []string
-	Parse   func(string) (int, error)
+	Parse   func(alias, name string) (int, error)
 }
 
 // Supported returns a slice of supported architecture information.
@@ -29,7 +37,15 @@ func (ArchInfo) Supported() []ArchInfo {
 	return []ArchInfo{
 		{
 			Type:    "amd64",
-			Aliases: []string{"x86_64", "x64", "win64"},
+			Aliases: []string{"x86_64", "x86-64", "x64", "win64"},
+			Parse: func(_, name string) (int, error) {
+				match := amd64Level.FindStringSubmatch(name)
+				if len(match) > 1 {
+					return strconv.Atoi(match[1])
+				}
+
+				return 0, nil
+			},
 		},
 		{
 			Type:    "386",
@@ -39,10 +55,43 @@ func (ArchInfo) Supported() []ArchInfo {
 			Type:    "arm64",
 			Aliases: []string{"aarch64"},
 		},
+		{
+			Type:    "riscv64",
+			Aliases: []string{"riscv64gc"},
+		},
+		{
+			Type:    "loong64",
+			Aliases: []string{"loongarch64"},
+		},
+		{
+			Type:    "ppc64le",
+			Aliases: []string{"powerpc64le", "ppc64el"},
+		},
+		{
+			Type:    "ppc64",
+			Aliases: []string{"powerpc64"},
+		},
+		{
+			Type: "s390x",
+		},
+		{
+			Type:    "mips64le",
+			Aliases: []string{"mips64el"},
+		},
+		{
+			Type: "mips64",
+		},
+		{
+			Type:    "mipsle",
+			Aliases: []string{"mipsel"},
+		},
+		{
+			Type: "mips",
+		},
 		{
 			Type:    "arm",
 			Aliases: []string{"armv7", "armv6", "armv5", "armel", "armhf", "arm"},
-			Parse: func(s string) (int, error) {
+			Parse: func(s, _ string) (int, error) {
 				switch s {
 				case "armel", "arm":
 					return 5, nil
@@ -50,8 +99,7 @@ func (ArchInfo) Supported() []ArchInfo {
 					return 7, nil // (or 6)
 				}
 
-				re := regexp.MustCompile(`armv(\d+)`)
-				match := re.FindStringSubmatch(s)
+				match := armVersion.FindStringSubmatch(s)
 				if len(match) > 1 {
 					return strconv.Atoi(match[1])
 				}
@@ -68,6 +116,8 @@ func (a *Architecture) Parse(name string) error {
 
 	info := ArchInfo{}
 
+	a.Version = 0
+
 	for _, info := range info.Supported() {
 		for i, alias := range append([]string{info.Type}, info.Aliases...) {
 			if info.Type == "arm" && i == 0 {
@@ -79,7 +129,7 @@ func (a *Architecture) Parse(name string) error {
 				a.Type = info.Type
 				a.Raw = alias
 				if info.Parse != nil {
-					version, err := info.Parse(alias)
+					version, err := info.Parse(alias, name)
 					if err != nil {
 						return err
 					}
@@ -109,6 +159,11 @@ func (a Architecture) IsCompatibleWith(other Architecture) bool {
 		return false
 	}
 
+	// Without a known microarchitecture level, only baseline amd64 (v1) is assumed to be supported.
+	if a.Type == "amd64" && other.Type == "amd64" {
+		return other.Version <= max(a.Version, 1)
+	}
+
 	if a.Is(other) {
 		return true
 	}
@@ -141,6 +196,7 @@ func (a *Architecture) To32BitUserLand() {
 	switch a.Type {
 	case "amd64":
 		a.Type = "386"
+		a.Version = 0
 	case "arm64":
 		a.Type = "arm"
 		a.Version = 7
@@ -149,7 +205,7 @@ func (a *Architecture) To32BitUserLand() {
 }
 
 func (a Architecture) Is64Bit() bool {
-	return strings.Contains(a.Type, "64")
+	return strings.Contains(a.Type, "64") || a.Type == "s390x"
 }
 
 func Is32Bit() (bool, error) {
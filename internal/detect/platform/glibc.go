@@ -0,0 +1,67 @@
+package platform
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrGlibcNotFound is returned when the version of the GNU C library cannot be determined.
+var ErrGlibcNotFound = errors.New("unable to determine glibc version")
+
+// glibcLocations lists glob patterns for the dynamic loader and the C library,
+// both of which print their version when executed.
+var glibcLocations = []string{
+	"/lib*/ld-linux*.so.*",
+	"/lib/*-linux-gnu*/ld-linux*.so.*",
+	"/lib*/libc.so.6",
+	"/lib/*-linux-gnu*/libc.so.6",
+	"/usr/lib*/libc.so.6",
+	"/usr/lib/*-linux-gnu*/libc.so.6",
+}
+
+var glibcVersionPattern = regexp.MustCompile(`(?i)(?:glibc|version) (\d+\.\d+)`)
+
+// GlibcVersion detects the version of the GNU C library installed on the host, such as "2.17".
+// It first queries `getconf`, and falls back to executing the dynamic loader or libc.so.6.
+func GlibcVersion() (string, error) {
+	if version, err := glibcVersionFrom("getconf", "GNU_LIBC_VERSION"); err == nil {
+		return version, nil
+	}
+
+	for _, pattern := range glibcLocations {
+		matches, _ := filepath.Glob(pattern)
+
+		for _, match := range matches {
+			if version, err := glibcVersionFrom(match, "--version"); err == nil {
+				return version, nil
+			}
+
+			if version, err := glibcVersionFrom(match); err == nil {
+				return version, nil
+			}
+		}
+	}
+
+	return "", ErrGlibcNotFound
+}
+
+// glibcVersionFrom runs the given command and extracts the glibc version from its output.
+func glibcVersionFrom(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	// Loaders and libc.so.6 may exit with a non-zero status even when printing their version.
+	_ = cmd.Run()
+
+	matches := glibcVersionPattern.FindStringSubmatch(out.String())
+	if matches == nil {
+		return "", ErrGlibcNotFound
+	}
+
+	return matches[1], nil
+}
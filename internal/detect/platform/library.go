@@ -3,12 +3,15 @@ package platform
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 // Library represents a system library or ABI (Application Binary Interface).
 type Library struct {
-	Type string
-	Raw  string // Original parsed library value
+	Type    string
+	Raw     string // Original parsed library value
+	Version string // Version of the library, currently only detected for glibc
 }
 
 // LibraryInfo holds information about a library type, including aliases.
@@ -105,6 +108,26 @@ func (l Library) IsCompatibleWith(other Library) bool {
 	return false
 }
 
+// SupportsVersion checks whether the library satisfies the required minimum version.
+// It returns true if either version is unknown.
+func (l Library) SupportsVersion(required string) bool {
+	if l.Version == "" || required == "" {
+		return true
+	}
+
+	available, err := semver.NewVersion(l.Version)
+	if err != nil {
+		return true
+	}
+
+	minimum, err := semver.NewVersion(required)
+	if err != nil {
+		return true
+	}
+
+	return !available.LessThan(minimum)
+}
+
 // String returns a string representation of the library.
 func (l Library) String() string {
 	return l.Type
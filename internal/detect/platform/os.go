@@ -2,6 +2,7 @@ package platform
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -43,9 +44,25 @@ func (OSInfo) Supported() []OSInfo {
 		{
 			Type: "openbsd",
 		},
+		{
+			Type: "dragonfly",
+		},
+		{
+			Type: "illumos",
+		},
+		{
+			Type:    "solaris",
+			Aliases: []string{"sunos"},
+		},
 	}
 }
 
+// osCompatibility lists the operating systems whose binaries can additionally be run on a given OS.
+var osCompatibility = map[string][]string{
+	// illumos is derived from OpenSolaris and runs most Solaris binaries.
+	"illumos": {"solaris"},
+}
+
 // Parse attempts to parse the OS from the given name string.
 func (o *OS) Parse(name string) error {
 	name = strings.ToLower(name)
@@ -81,7 +98,11 @@ func (o OS) IsCompatibleWith(other OS) bool {
 		return false
 	}
 
-	return o.Type == other.Type
+	if o.Type == other.Type {
+		return true
+	}
+
+	return slices.Contains(osCompatibility[o.Type], other.Type)
 }
 
 // String returns a string representation of the OS.
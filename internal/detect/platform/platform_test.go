@@ -1 +1,79 @@
 package platform_test
+
+import (
+	"testing"
+
+	"github.com/idelchi/godyl/internal/detect/platform"
+)
+
+func TestArchitectureParseAmd64Levels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version int
+	}{
+		{name: "tool_linux_amd64.tar.gz", version: 0},
+		{name: "tool-x86_64-unknown-linux-gnu.tar.gz", version: 0},
+		{name: "tool_linux_amd64v1.tar.gz", version: 1},
+		{name: "tool_linux_amd64_v2.tar.gz", version: 2},
+		{name: "tool-x86_64_v2-linux.tar.gz", version: 2},
+		{name: "tool_linux_amd64v3.tar.gz", version: 3},
+		{name: "tool-x86-64-v3-linux.tar.gz", version: 3},
+		{name: "tool-linux-X86_64-V4.tar.gz", version: 4},
+		{name: "tool_linux_x64v4.zip", version: 4},
+		{name: "tool_linux_amd64v5.tar.gz", version: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var arch platform.Architecture
+			if err := arch.Parse(tt.name); err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.name, err)
+			}
+
+			if arch.Type != "amd64" || arch.Version != tt.version {
+				t.Errorf("Parse(%q) = %s v%d, want amd64 v%d", tt.name, arch.Type, arch.Version, tt.version)
+			}
+		})
+	}
+}
+
+func TestArchitectureIsCompatibleWith(t *testing.T) {
+	t.Parallel()
+
+	amd64 := func(version int) platform.Architecture {
+		return platform.Architecture{Type: "amd64", Version: version, Raw: "amd64"}
+	}
+
+	tests := []struct {
+		name  string
+		host  platform.Architecture
+		asset platform.Architecture
+		want  bool
+	}{
+		{name: "v3 host, baseline asset", host: amd64(3), asset: amd64(0), want: true},
+		{name: "v3 host, v1 asset", host: amd64(3), asset: amd64(1), want: true},
+		{name: "v3 host, v2 asset", host: amd64(3), asset: amd64(2), want: true},
+		{name: "v3 host, v3 asset", host: amd64(3), asset: amd64(3), want: true},
+		{name: "v3 host, v4 asset", host: amd64(3), asset: amd64(4), want: false},
+		{name: "unknown host, baseline asset", host: amd64(0), asset: amd64(0), want: true},
+		{name: "unknown host, v1 asset", host: amd64(0), asset: amd64(1), want: true},
+		{name: "unknown host, v2 asset", host: amd64(0), asset: amd64(2), want: false},
+		{name: "v1 host, v2 asset", host: amd64(1), asset: amd64(2), want: false},
+		{name: "other type", host: amd64(3), asset: platform.Architecture{Type: "arm64", Raw: "arm64"}, want: false},
+		{name: "unset asset", host: amd64(3), asset: platform.Architecture{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.host.IsCompatibleWith(tt.asset); got != tt.want {
+				t.Errorf("%s.IsCompatibleWith(%s) = %v, want %v", tt.host, tt.asset, got, tt.want)
+			}
+		})
+	}
+}
@@ -26,8 +26,29 @@ func (as Assets) FilterByName(name string) (assets Assets) {
 // Match checks if the assets match the given requirements.
 // It processes each asset to extract platform and extension information.
 func (as Assets) Match(requirements match.Requirements) (matches match.Results) {
-	var assets match.Assets
+	// Select the assets that satisfy the given requirements.
+	return as.parse().Select(requirements)
+}
+
+// Evaluate scores all assets against the given requirements, explaining the contribution of each criterion.
+func (as Assets) Evaluate(requirements match.Requirements) match.Evaluations {
+	return as.parse().Evaluate(requirements)
+}
+
+// Candidates returns all qualified assets with a positive score, sorted from best to worst.
+// It is used to fall back to the next-best asset when the best one turns out to be unsuitable.
+func (as Assets) Candidates(requirements match.Requirements) (candidates match.Results) {
+	for _, result := range as.parse().Match(requirements).Sorted() {
+		if result.Qualified && result.Score > 0 {
+			candidates = append(candidates, result)
+		}
+	}
+
+	return candidates
+}
 
+// parse converts the assets into matchable assets, extracting platform and extension information.
+func (as Assets) parse() (assets match.Assets) {
 	for _, a := range as {
 		asset := match.Asset{Name: a.Name}
 		asset.Parse() // Parse the asset name to extract additional info (platform, architecture, etc.)
@@ -38,8 +59,5 @@ func (as Assets) Match(requirements match.Requirements) (matches match.Results)
 		assets = append(assets, asset)
 	}
 
-	// Select the assets that satisfy the given requirements.
-	matches = assets.Select(requirements)
-
-	return matches
+	return assets
 }
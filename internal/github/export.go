@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -56,11 +57,11 @@ func (g *Repository) Export(release *Release) error {
 }
 
 // LatestReleaseFromExport retrieves the latest release information from the exported JSON file.
-func (g *Repository) LatestReleaseFromExport() (*Release, error) {
+func (g *Repository) LatestReleaseFromExport(ctx context.Context) (*Release, error) {
 	// Read the exported file
 	fileData, err := os.ReadFile(importFile)
 	if err != nil {
-		return g.LatestRelease()
+		return g.LatestRelease(ctx)
 	}
 
 	var data map[string][]Asset
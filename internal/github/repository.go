@@ -2,41 +2,51 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 
 	"github.com/google/go-github/v64/github"
 )
 
+// ErrRateLimited is returned when the GitHub API refuses requests due to rate limiting.
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// rateLimited marks errors caused by the rate limits of the GitHub API with ErrRateLimited.
+func rateLimited(err error) error {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+
+	if errors.As(err, &rateErr) || errors.As(err, &abuseErr) {
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+
+	return err
+}
+
 // Repository represents a GitHub repository with its owner and name.
-// It contains a GitHub client and context for making API calls.
+// It contains a GitHub client for making API calls.
 type Repository struct {
-	Owner  string          // Owner is the owner of the repository (GitHub username or organization).
-	Repo   string          // Repo is the name of the repository.
-	client *github.Client  // client is the GitHub client used to interact with the GitHub API.
-	ctx    context.Context // ctx is the context used for API requests.
+	Owner  string         // Owner is the owner of the repository (GitHub username or organization).
+	Repo   string         // Repo is the name of the repository.
+	client *github.Client // client is the GitHub client used to interact with the GitHub API.
 }
 
 // NewRepository creates a new instance of Repository.
 // It requires the repository owner, repository name, and a GitHub client.
 func NewRepository(owner, repo string, client *github.Client) *Repository {
-	ctx := context.Background()
-
 	return &Repository{
 		Owner:  owner,
 		Repo:   repo,
 		client: client,
-		ctx:    ctx,
 	}
 }
 
 // LatestRelease retrieves the latest release for the repository.
-func (g *Repository) LatestRelease() (*Release, error) {
-	ctx := context.TODO()
-
+func (g *Repository) LatestRelease(ctx context.Context) (*Release, error) {
 	repositoryRelease, _, err := g.client.Repositories.GetLatestRelease(ctx, g.Owner, g.Repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest release: %w", err)
+		return nil, fmt.Errorf("failed to get latest release: %w", rateLimited(err))
 	}
 
 	release := &Release{}
@@ -49,12 +59,10 @@ func (g *Repository) LatestRelease() (*Release, error) {
 }
 
 // GetRelease retrieves a specific release for the repository based on the provided tag.
-func (g *Repository) GetRelease(tag string) (*Release, error) {
-	ctx := context.TODO()
-
+func (g *Repository) GetRelease(ctx context.Context, tag string) (*Release, error) {
 	repositoryRelease, _, err := g.client.Repositories.GetReleaseByTag(ctx, g.Owner, g.Repo, tag)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get assets for release tag %q: %w", tag, err)
+		return nil, fmt.Errorf("failed to get assets for release tag %q: %w", tag, rateLimited(err))
 	}
 
 	release := &Release{}
@@ -67,12 +75,10 @@ func (g *Repository) GetRelease(tag string) (*Release, error) {
 }
 
 // Languages retrieves the programming languages used in the repository, sorted by usage in descending order.
-func (g *Repository) Languages() ([]string, error) {
-	ctx := context.TODO()
-
+func (g *Repository) Languages(ctx context.Context) ([]string, error) {
 	languages, _, err := g.client.Repositories.ListLanguages(ctx, g.Owner, g.Repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get languages: %w", err)
+		return nil, fmt.Errorf("failed to get languages: %w", rateLimited(err))
 	}
 
 	// Create a slice of keys to sort
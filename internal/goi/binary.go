@@ -1,17 +1,20 @@
 package goi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 
 	"github.com/idelchi/godyl/pkg/download"
 	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/lock"
 )
 
 // Binary represents a Go binary, including its associated file, directory, and environment variables.
@@ -21,13 +24,22 @@ type Binary struct {
 	Env  Env         // Env contains the environment variables for running the binary.
 
 	noVerifySSL bool
+	// locked indicates that the binary holds a use of the lock on the shared directory.
+	locked bool
 }
 
-var mu sync.Mutex
+var (
+	mu sync.Mutex
+	// held is the lock on the directory shared with other processes, and users the number of binaries using it.
+	held  *lock.Lock
+	users int
+)
 
 // New creates a new Binary instance, setting up the directory, downloading the latest release if necessary,
-// and initializing environment variables. It ensures thread-safe execution by using a mutex lock.
-func New(noVerifySSL bool) (binary Binary, err error) {
+// and initializing environment variables. It ensures thread-safe execution by using a mutex lock,
+// and serializes with other processes sharing the directory through a file lock, waiting up to the timeout.
+// The lock is held until the binary is released, as running it uses the shared directory as well.
+func New(ctx context.Context, noVerifySSL bool, lockTimeout time.Duration) (binary Binary, err error) {
 	mu.Lock()
 	defer mu.Unlock()
 
@@ -38,6 +50,30 @@ func New(noVerifySSL bool) (binary Binary, err error) {
 		return binary, fmt.Errorf("creating temp dir: %w", err)
 	}
 
+	if users == 0 {
+		lockPath := dir.Path() + ".lock"
+
+		if held, err = lock.Acquire(ctx, lockPath, lockTimeout); err != nil {
+			if errors.Is(err, lock.ErrLocked) {
+				return binary, fmt.Errorf("another godyl is installing Go into %q (pid %d): %w", dir, lock.Holder(lockPath), err)
+			}
+
+			return binary, err
+		}
+	}
+
+	users++
+
+	binary.locked = true
+
+	defer func() {
+		if err != nil {
+			binary.locked = false
+
+			release()
+		}
+	}()
+
 	if file, err := binary.Find(dir.Path()); err == nil {
 		binary.File = file
 		if dir.IsParentOf(file.Dir()) {
@@ -52,7 +88,7 @@ func New(noVerifySSL bool) (binary Binary, err error) {
 		binary.Dir = dir
 	}
 
-	release, err := binary.Latest()
+	release, err := binary.Latest(ctx)
 	if err != nil {
 		return binary, err
 	}
@@ -69,9 +105,10 @@ func New(noVerifySSL bool) (binary Binary, err error) {
 		return binary, err
 	}
 
-	err = binary.Download(path[0].Asset.Name)
+	err = binary.Download(ctx, path[0].Asset.Name)
 	if err != nil {
-		return binary, err
+		// Do not leave a partial toolchain behind for the next run to find.
+		return binary, errors.Join(err, binary.CleanUp())
 	}
 
 	binary.Env.Default(binary.Dir.Path())
@@ -79,6 +116,26 @@ func New(noVerifySSL bool) (binary Binary, err error) {
 	return binary, nil
 }
 
+// Release releases the binary, unlocking the shared directory once no other binary uses it.
+func (b *Binary) Release() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if b.locked {
+		b.locked = false
+
+		release()
+	}
+}
+
+// release drops a user of the lock on the shared directory, releasing it once unused. mu must be held.
+func release() {
+	if users--; users == 0 {
+		held.Release()
+		held = nil
+	}
+}
+
 // Find searches for the Go binary in the given paths or system path, returning the file if found.
 func (b *Binary) Find(paths ...string) (file.File, error) {
 	binary, err := exec.LookPath("go")
@@ -99,13 +156,13 @@ func (b *Binary) Find(paths ...string) (file.File, error) {
 
 // Download downloads the Go binary from the provided path and saves it to the directory.
 // It returns an error if the download or file validation fails.
-func (b *Binary) Download(path string) error {
+func (b *Binary) Download(ctx context.Context, path string) error {
 	url := fmt.Sprintf("https://go.dev/dl/%s", path)
 
 	downloader := download.New()
 	downloader.InsecureSkipVerify = b.noVerifySSL
 
-	destination, err := downloader.Download(url, b.Dir.Path())
+	destination, err := downloader.Download(ctx, url, b.Dir.Path())
 	if err != nil {
 		return fmt.Errorf("downloading %q: %w", url, err)
 	}
@@ -133,9 +190,9 @@ func (b *Binary) CleanUp() error {
 
 // Latest fetches the latest Go release information from the official Go download page.
 // It returns the most recent release or an error if the process fails.
-func (b Binary) Latest() (Release, error) {
+func (b Binary) Latest(ctx context.Context) (Release, error) {
 	client := resty.New()
-	resp, err := client.R().Get("https://go.dev/dl/?mode=json")
+	resp, err := client.R().SetContext(ctx).Get("https://go.dev/dl/?mode=json")
 	if err != nil {
 		return Release{}, err
 	}
@@ -2,6 +2,7 @@ package goi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,13 +13,13 @@ type Installer struct {
 	Binary Binary // Binary represents the Go binary used for the installation process.
 }
 
-// Install executes the `go install` command for the provided package path.
+// Install executes the `go install` command for the provided package path, which is killed if the context is cancelled.
 // It captures both stdout and stderr, returning them as output, and reports errors if the installation fails.
-func (i *Installer) Install(path string) (output string, err error) {
+func (i *Installer) Install(ctx context.Context, path string) (output string, err error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 
 	// Prepare the command
-	cmd := exec.Command(i.Binary.File.Name(), "install", path)
+	cmd := exec.CommandContext(ctx, i.Binary.File.Name(), "install", path)
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, i.Binary.Env.ToSlice()...)
 
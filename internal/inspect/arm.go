@@ -0,0 +1,161 @@
+package inspect
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+)
+
+// ARM EABI build attribute tags, see "Addenda to, and Errata in, the ABI for the Arm Architecture".
+const (
+	tagFile              = 1
+	tagCPURawName        = 4
+	tagCPUName           = 5
+	tagCPUArch           = 6
+	tagCompatibility     = 32
+	tagAlsoCompatibleWit = 65
+	tagConformance       = 67
+)
+
+// armArchitectures maps the values of Tag_CPU_arch to ARM architecture versions.
+var armArchitectures = map[uint64]int{
+	1:  4, // v4
+	2:  4, // v4T
+	3:  5, // v5T
+	4:  5, // v5TE
+	5:  5, // v5TEJ
+	6:  6, // v6
+	7:  6, // v6KZ
+	8:  6, // v6T2
+	9:  6, // v6K
+	10: 7, // v7
+	11: 6, // v6-M
+	12: 6, // v6S-M
+	13: 7, // v7E-M
+	14: 8, // v8-A
+}
+
+// armVersion reads the ARM architecture version from the `.ARM.attributes` section of an ELF file.
+// It returns 0 if the section is missing or cannot be parsed.
+func armVersion(e *elf.File) int {
+	section := e.Section(".ARM.attributes")
+	if section == nil {
+		return 0
+	}
+
+	data, err := section.Data()
+	if err != nil || len(data) == 0 || data[0] != 'A' {
+		return 0
+	}
+
+	data = data[1:]
+
+	// The section consists of vendor subsections: <length:uint32> <vendor:NTBS> <sub-subsections...>
+	for len(data) >= 4 {
+		length := int(binary.LittleEndian.Uint32(data))
+		if length < 4 || length > len(data) {
+			return 0
+		}
+
+		subsection := data[4:length]
+		data = data[length:]
+
+		vendorEnd := bytes.IndexByte(subsection, 0)
+		if vendorEnd < 0 || string(subsection[:vendorEnd]) != "aeabi" {
+			continue
+		}
+
+		if version := armVersionFromAttributes(subsection[vendorEnd+1:]); version != 0 {
+			return version
+		}
+	}
+
+	return 0
+}
+
+// armVersionFromAttributes scans the file-scope attributes of an "aeabi" subsection for Tag_CPU_arch.
+func armVersionFromAttributes(data []byte) int {
+	for len(data) > 0 {
+		tag, n := uleb128(data)
+		if n == 0 || len(data) < n+4 {
+			return 0
+		}
+
+		size := int(binary.LittleEndian.Uint32(data[n:]))
+		if size < n+4 || size > len(data) {
+			return 0
+		}
+
+		attributes := data[n+4 : size]
+		data = data[size:]
+
+		if tag != tagFile {
+			continue
+		}
+
+		for len(attributes) > 0 {
+			attribute, n := uleb128(attributes)
+			if n == 0 {
+				return 0
+			}
+
+			attributes = attributes[n:]
+
+			switch {
+			case attribute == tagCPUArch:
+				value, _ := uleb128(attributes)
+
+				return armArchitectures[value]
+			case attribute == tagCompatibility:
+				_, n := uleb128(attributes)
+				attributes = skipString(attributes[n:])
+			case isStringAttribute(attribute):
+				attributes = skipString(attributes)
+			default:
+				_, n := uleb128(attributes)
+				attributes = attributes[n:]
+			}
+		}
+	}
+
+	return 0
+}
+
+// isStringAttribute reports whether the attribute carries a null-terminated string value.
+// Tags above 32 follow the convention that odd tags carry strings, while even tags carry integers.
+func isStringAttribute(tag uint64) bool {
+	switch tag {
+	case tagCPURawName, tagCPUName, tagAlsoCompatibleWit, tagConformance:
+		return true
+	}
+
+	return tag > tagCompatibility && tag%2 == 1
+}
+
+// skipString advances past a null-terminated string.
+func skipString(data []byte) []byte {
+	end := bytes.IndexByte(data, 0)
+	if end < 0 {
+		return nil
+	}
+
+	return data[end+1:]
+}
+
+// uleb128 decodes an unsigned LEB128 value, returning the value and the number of bytes consumed.
+func uleb128(data []byte) (uint64, int) {
+	var value uint64
+
+	for i, b := range data {
+		if i == 10 {
+			return 0, 0
+		}
+
+		value |= uint64(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+
+	return 0, 0
+}
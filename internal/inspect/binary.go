@@ -0,0 +1,235 @@
+package inspect
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+var (
+	// ErrNotBinary is returned when a file is not a recognized executable format.
+	ErrNotBinary = errors.New("not a recognized binary format")
+	// ErrMismatch is returned when a binary does not target the requested platform.
+	ErrMismatch = errors.New("binary does not match the requested platform")
+	// ErrUnsupported is returned when the headers of a binary cannot be parsed, or name an unknown architecture.
+	ErrUnsupported = errors.New("unsupported binary")
+)
+
+// Format represents the file format of an executable.
+type Format string
+
+const (
+	// ELF is the format used by Linux, the BSDs and Android.
+	ELF Format = "elf"
+	// MachO is the format used by macOS.
+	MachO Format = "macho"
+	// PE is the format used by Windows.
+	PE Format = "pe"
+)
+
+// Binary describes the platform an executable file was built for, as read from its headers.
+type Binary struct {
+	// Format is the file format of the binary.
+	Format Format
+	// OS is the operating system the binary targets, if it could be determined.
+	OS platform.OS
+	// Architectures lists the architectures contained in the binary.
+	// Universal (fat) Mach-O binaries may contain more than one.
+	Architectures []platform.Architecture
+	// Library is the C library the binary is dynamically linked against, if any.
+	Library platform.Library
+	// GlibcVersion is the newest glibc symbol version required by the binary, such as "2.17", if any.
+	GlibcVersion string
+	// Static indicates whether the binary is statically linked.
+	Static bool
+	// Executable indicates whether the file is an executable (as opposed to, e.g., a shared library).
+	Executable bool
+	// Interpreter is the dynamic loader requested by the binary, if any.
+	Interpreter string
+}
+
+// Inspect reads the headers of the given file and returns the platform information it contains.
+// It returns ErrNotBinary if the file is not an ELF, Mach-O or PE file, and ErrUnsupported if its headers
+// cannot be parsed. Errors opening or reading the file are returned as they are.
+func Inspect(f file.File) (Binary, error) {
+	format, err := detectFormat(f)
+	if err != nil {
+		return Binary{}, err
+	}
+
+	var binary Binary
+
+	switch format {
+	case ELF:
+		binary, err = inspectELF(f)
+	case MachO:
+		binary, err = inspectMachO(f)
+	case PE:
+		binary, err = inspectPE(f)
+	default:
+		return Binary{}, fmt.Errorf("%w: %q", ErrNotBinary, f)
+	}
+
+	var pathErr *fs.PathError
+
+	switch {
+	case err == nil, errors.Is(err, ErrNotBinary), errors.As(err, &pathErr):
+		return binary, err
+	default:
+		return binary, fmt.Errorf("%w: %w", ErrUnsupported, err)
+	}
+}
+
+// detectFormat determines the binary format of a file from its magic bytes.
+// It returns an empty Format if the file is not a recognized binary.
+func detectFormat(f file.File) (Format, error) {
+	handle, err := os.Open(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("opening %q: %w", f, err)
+	}
+	defer handle.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(handle, magic); err != nil {
+		// Files shorter than the magic are not binaries.
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("reading %q: %w", f, err)
+	}
+
+	switch {
+	case bytes.Equal(magic, []byte(elf.ELFMAG)):
+		return ELF, nil
+	case bytes.HasPrefix(magic, []byte("MZ")):
+		// Text files may start with "MZ" as well, so the PE signature is checked too.
+		ok, err := hasPESignature(handle)
+		if err != nil {
+			return "", fmt.Errorf("reading %q: %w", f, err)
+		}
+
+		if ok {
+			return PE, nil
+		}
+
+		return "", nil
+	}
+
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		switch order.Uint32(magic) {
+		case macho.Magic32, macho.Magic64, macho.MagicFat:
+			return MachO, nil
+		}
+	}
+
+	return "", nil
+}
+
+// hasPESignature checks whether the DOS header points to the "PE\0\0" signature of a PE file.
+func hasPESignature(r io.ReaderAt) (bool, error) {
+	// The offset of the signature is stored at the end of the 64 byte DOS header.
+	offset := make([]byte, 4)
+	if _, err := r.ReadAt(offset, 0x3c); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	signature := make([]byte, 4)
+	if _, err := r.ReadAt(signature, int64(binary.LittleEndian.Uint32(offset))); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return bytes.Equal(signature, []byte("PE\x00\x00")), nil
+}
+
+// ExpectedFormat returns the binary format used by the given operating system.
+func ExpectedFormat(os platform.OS) Format {
+	switch os.Type {
+	case "windows":
+		return PE
+	case "darwin":
+		return MachO
+	default:
+		return ELF
+	}
+}
+
+// Supports checks whether the binary can run on the given platform, natively or on one of the emulated architectures.
+// It returns an error wrapping ErrMismatch describing the first incompatibility found.
+func (b Binary) Supports(p detect.Platform, emulated ...platform.Architecture) error {
+	if p.OS.IsUnset() {
+		return nil
+	}
+
+	if expected := ExpectedFormat(p.OS); b.Format != expected {
+		return fmt.Errorf("%w: %s binary cannot run on %s (expected %s)", ErrMismatch, b.Format, p.OS, expected)
+	}
+
+	if !b.OS.IsUnset() && !p.OS.IsCompatibleWith(b.OS) {
+		return fmt.Errorf("%w: binary targets OS %q, requested %q", ErrMismatch, b.OS, p.OS)
+	}
+
+	if !p.Architecture.IsUnset() && !b.supportsArchitecture(append([]platform.Architecture{p.Architecture}, emulated...)...) {
+		return fmt.Errorf("%w: binary targets architecture %v, requested %q", ErrMismatch, b.Architectures, p.Architecture)
+	}
+
+	if !b.Static && !b.Library.IsUnset() && !p.Library.IsUnset() && b.Library.Type != p.Library.Type {
+		return fmt.Errorf(
+			"%w: binary is dynamically linked against %q, requested %q",
+			ErrMismatch,
+			b.Library,
+			p.Library,
+		)
+	}
+
+	if !b.Static && b.Library.Type == "gnu" && !p.Library.SupportsVersion(b.GlibcVersion) {
+		return fmt.Errorf(
+			"%w: binary requires glibc %s, available %s",
+			ErrMismatch,
+			b.GlibcVersion,
+			p.Library.Version,
+		)
+	}
+
+	return nil
+}
+
+// supportsArchitecture checks whether any of the binary's architectures can run on any of the given architectures.
+func (b Binary) supportsArchitecture(archs ...platform.Architecture) bool {
+	for _, arch := range archs {
+		for _, candidate := range b.Architectures {
+			if arch.IsCompatibleWith(candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// newArchitecture creates an architecture of the given type and version, as parsed from a binary header.
+func newArchitecture(typ string, version int) platform.Architecture {
+	return platform.Architecture{
+		Type:    typ,
+		Version: version,
+		Raw:     typ,
+	}
+}
@@ -0,0 +1,8 @@
+// Package inspect provides functionality for reading the headers of executable files
+// (ELF, Mach-O and PE) in order to determine the platform they were built for.
+// It extracts the operating system, the architecture (including the ARM version),
+// and whether the binary is statically linked or dynamically linked against musl or glibc.
+//
+// The information is used to verify that a downloaded binary actually targets the
+// requested platform, instead of relying solely on the name of the asset.
+package inspect
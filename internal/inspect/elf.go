@@ -0,0 +1,204 @@
+package inspect
+
+import (
+	"debug/elf"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// inspectELF reads the headers of an ELF file.
+func inspectELF(f file.File) (Binary, error) {
+	binary := Binary{Format: ELF}
+
+	e, err := elf.Open(f.Name())
+	if err != nil {
+		return binary, fmt.Errorf("opening ELF file %q: %w", f, err)
+	}
+	defer e.Close()
+
+	arch, err := elfArchitecture(e)
+	if err != nil {
+		return binary, err
+	}
+
+	binary.Architectures = []platform.Architecture{arch}
+
+	for _, prog := range e.Progs {
+		if prog.Type != elf.PT_INTERP {
+			continue
+		}
+
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return binary, fmt.Errorf("reading interpreter of %q: %w", f, err)
+		}
+
+		binary.Interpreter = strings.TrimRight(string(data), "\x00")
+	}
+
+	// DT_NEEDED is absent for statically linked binaries.
+	needed, _ := e.DynString(elf.DT_NEEDED)
+
+	binary.Static = binary.Interpreter == "" && len(needed) == 0
+	binary.Library = elfLibrary(binary.Interpreter, needed)
+	binary.OS = elfOS(e, binary.Interpreter)
+	binary.GlibcVersion = elfGlibcVersion(e)
+	binary.Executable = elfIsExecutable(e, binary.Interpreter)
+
+	return binary, nil
+}
+
+// elfArchitecture determines the architecture from the machine type of an ELF file.
+func elfArchitecture(e *elf.File) (platform.Architecture, error) {
+	is64Bit := e.Class == elf.ELFCLASS64
+	isLittleEndian := e.Data == elf.ELFDATA2LSB
+
+	switch e.Machine {
+	case elf.EM_X86_64:
+		return newArchitecture("amd64", 0), nil
+	case elf.EM_386:
+		return newArchitecture("386", 0), nil
+	case elf.EM_AARCH64:
+		return newArchitecture("arm64", 0), nil
+	case elf.EM_ARM:
+		return newArchitecture("arm", armVersion(e)), nil
+	case elf.EM_RISCV:
+		if is64Bit {
+			return newArchitecture("riscv64", 0), nil
+		}
+
+		return newArchitecture("riscv", 0), nil
+	case elf.EM_PPC64:
+		if isLittleEndian {
+			return newArchitecture("ppc64le", 0), nil
+		}
+
+		return newArchitecture("ppc64", 0), nil
+	case elf.EM_S390:
+		return newArchitecture("s390x", 0), nil
+	case elf.EM_LOONGARCH:
+		return newArchitecture("loong64", 0), nil
+	case elf.EM_MIPS:
+		arch := "mips"
+		if is64Bit {
+			arch = "mips64"
+		}
+
+		if isLittleEndian {
+			arch += "le"
+		}
+
+		return newArchitecture(arch, 0), nil
+	default:
+		return platform.Architecture{}, fmt.Errorf("unsupported ELF machine type %v", e.Machine)
+	}
+}
+
+// elfLibrary determines the C library from the dynamic loader and the needed shared libraries.
+func elfLibrary(interpreter string, needed []string) platform.Library {
+	candidates := append([]string{interpreter}, needed...)
+
+	for _, candidate := range candidates {
+		switch {
+		case strings.Contains(candidate, "ld-musl"), strings.HasPrefix(candidate, "libc.musl"):
+			return platform.Library{Type: "musl", Raw: candidate}
+		case strings.Contains(candidate, "/system/bin/linker"):
+			return platform.Library{Type: "android", Raw: candidate}
+		case strings.Contains(candidate, "ld-linux"), candidate == "libc.so.6":
+			return platform.Library{Type: "gnu", Raw: candidate}
+		}
+	}
+
+	return platform.Library{}
+}
+
+// elfGlibcVersion returns the newest GLIBC_x.y symbol version required by an ELF file.
+// It returns an empty string if the binary does not import versioned glibc symbols.
+func elfGlibcVersion(e *elf.File) string {
+	symbols, err := e.ImportedSymbols()
+	if err != nil {
+		return ""
+	}
+
+	var newest *semver.Version
+
+	for _, symbol := range symbols {
+		raw, found := strings.CutPrefix(symbol.Version, "GLIBC_")
+		if !found {
+			continue
+		}
+
+		version, err := semver.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+
+		if newest == nil || version.GreaterThan(newest) {
+			newest = version
+		}
+	}
+
+	if newest == nil {
+		return ""
+	}
+
+	return newest.Original()
+}
+
+// elfOS determines the operating system from the ABI and the dynamic loader of an ELF file.
+// The OS is left unset when the binary uses the generic System V ABI and does not request a known loader.
+func elfOS(e *elf.File, interpreter string) platform.OS {
+	switch e.OSABI {
+	case elf.ELFOSABI_LINUX:
+		return platform.OS{Type: "linux", Raw: "linux"}
+	case elf.ELFOSABI_FREEBSD:
+		return platform.OS{Type: "freebsd", Raw: "freebsd"}
+	case elf.ELFOSABI_NETBSD:
+		return platform.OS{Type: "netbsd", Raw: "netbsd"}
+	case elf.ELFOSABI_OPENBSD:
+		return platform.OS{Type: "openbsd", Raw: "openbsd"}
+	case elf.ELFOSABI_SOLARIS:
+		return platform.OS{Type: "solaris", Raw: "solaris"}
+	}
+
+	switch {
+	case strings.Contains(interpreter, "/system/bin/linker"):
+		return platform.OS{Type: "android", Raw: "android"}
+	case strings.Contains(interpreter, "ld-linux"), strings.Contains(interpreter, "ld-musl"):
+		return platform.OS{Type: "linux", Raw: "linux"}
+	}
+
+	return platform.OS{}
+}
+
+// elfIsExecutable checks whether the ELF file is an executable rather than a shared object.
+// Position-independent executables share their file type with shared objects,
+// and are identified by their loader or the PIE flag.
+func elfIsExecutable(e *elf.File, interpreter string) bool {
+	switch e.Type {
+	case elf.ET_EXEC:
+		return true
+	case elf.ET_DYN:
+		if interpreter != "" {
+			return true
+		}
+
+		flags, err := e.DynValue(elf.DT_FLAGS_1)
+		if err != nil {
+			return false
+		}
+
+		for _, flag := range flags {
+			if elf.DynFlag1(flag)&elf.DF_1_PIE != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1 @@
+package inspect_test
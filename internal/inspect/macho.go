@@ -0,0 +1,75 @@
+package inspect
+
+import (
+	"debug/macho"
+	"errors"
+	"fmt"
+
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// inspectMachO reads the headers of a Mach-O file, including universal (fat) binaries.
+func inspectMachO(f file.File) (Binary, error) {
+	binary := Binary{
+		Format: MachO,
+		OS:     platform.OS{Type: "darwin", Raw: "darwin"},
+	}
+
+	fat, err := macho.OpenFat(f.Name())
+	switch {
+	case err == nil:
+		defer fat.Close()
+
+		for _, arch := range fat.Arches {
+			architecture, err := machoArchitecture(arch.Cpu)
+			if err != nil {
+				continue
+			}
+
+			binary.Architectures = append(binary.Architectures, architecture)
+			binary.Executable = binary.Executable || arch.Type == macho.TypeExec
+		}
+
+		if len(binary.Architectures) == 0 {
+			return binary, fmt.Errorf("no supported architectures found in universal binary %q", f)
+		}
+
+		return binary, nil
+	case !errors.Is(err, macho.ErrNotFat):
+		// Java class files share their magic number with universal binaries.
+		return binary, fmt.Errorf("%w: %q: %w", ErrNotBinary, f, err)
+	}
+
+	m, err := macho.Open(f.Name())
+	if err != nil {
+		return binary, fmt.Errorf("opening Mach-O file %q: %w", f, err)
+	}
+	defer m.Close()
+
+	architecture, err := machoArchitecture(m.Cpu)
+	if err != nil {
+		return binary, err
+	}
+
+	binary.Architectures = []platform.Architecture{architecture}
+	binary.Executable = m.Type == macho.TypeExec
+
+	return binary, nil
+}
+
+// machoArchitecture determines the architecture from the CPU type of a Mach-O file.
+func machoArchitecture(cpu macho.Cpu) (platform.Architecture, error) {
+	switch cpu {
+	case macho.CpuAmd64:
+		return newArchitecture("amd64", 0), nil
+	case macho.Cpu386:
+		return newArchitecture("386", 0), nil
+	case macho.CpuArm64:
+		return newArchitecture("arm64", 0), nil
+	case macho.CpuArm:
+		return newArchitecture("arm", 0), nil
+	default:
+		return platform.Architecture{}, fmt.Errorf("unsupported Mach-O CPU type %v", cpu)
+	}
+}
@@ -0,0 +1,49 @@
+package inspect
+
+import (
+	"debug/pe"
+	"fmt"
+
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// inspectPE reads the headers of a PE file.
+func inspectPE(f file.File) (Binary, error) {
+	binary := Binary{
+		Format: PE,
+		OS:     platform.OS{Type: "windows", Raw: "windows"},
+	}
+
+	p, err := pe.Open(f.Name())
+	if err != nil {
+		return binary, fmt.Errorf("opening PE file %q: %w", f, err)
+	}
+	defer p.Close()
+
+	architecture, err := peArchitecture(p.Machine)
+	if err != nil {
+		return binary, err
+	}
+
+	binary.Architectures = []platform.Architecture{architecture}
+	binary.Executable = p.Characteristics&pe.IMAGE_FILE_DLL == 0
+
+	return binary, nil
+}
+
+// peArchitecture determines the architecture from the machine type of a PE file.
+func peArchitecture(machine uint16) (platform.Architecture, error) {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return newArchitecture("amd64", 0), nil
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return newArchitecture("386", 0), nil
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return newArchitecture("arm64", 0), nil
+	case pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_ARM, pe.IMAGE_FILE_MACHINE_THUMB:
+		return newArchitecture("arm", 7), nil
+	default:
+		return platform.Architecture{}, fmt.Errorf("unsupported PE machine type %#x", machine)
+	}
+}
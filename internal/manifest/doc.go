@@ -0,0 +1,3 @@
+// Package manifest records the files godyl placed for a tool besides its executables,
+// such as man pages, shell completions and licenses, so that they can be replaced or removed cleanly.
+package manifest
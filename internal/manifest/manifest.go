@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// Folder is the folder, relative to the output folder, in which the manifests are stored.
+const Folder = ".godyl"
+
+// Manifest lists the files placed for a tool.
+type Manifest struct {
+	Tool  string   `json:"tool"`
+	Files []string `json:"files"`
+
+	path string
+}
+
+// Path returns the path of the manifest for the executable installed into the output folder.
+func Path(output, exe string) string {
+	return filepath.Join(output, Folder, exe+".json")
+}
+
+// Load reads the manifest from the given path.
+// A missing manifest results in an empty one, which is saved to the same path.
+func Load(path string) (Manifest, error) {
+	manifest := Manifest{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return manifest, nil
+		}
+
+		return manifest, fmt.Errorf("reading manifest %q: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("parsing manifest %q: %w", path, err)
+	}
+
+	return manifest, nil
+}
+
+// Add records the files as placed, skipping files which are already recorded.
+func (m *Manifest) Add(files ...string) {
+	for _, file := range files {
+		if !slices.Contains(m.Files, file) {
+			m.Files = append(m.Files, file)
+		}
+	}
+}
+
+// Stale returns the recorded files which are not among the given files.
+func (m Manifest) Stale(files []string) []string {
+	var stale []string
+
+	for _, file := range m.Files {
+		if !slices.Contains(files, file) {
+			stale = append(stale, file)
+		}
+	}
+
+	return stale
+}
+
+// Prune removes the folders of the removed files which are left empty, up to the output folder.
+func (m Manifest) Prune(removed ...string) {
+	output := filepath.Dir(filepath.Dir(m.path))
+
+	for _, file := range removed {
+		// Removing a folder fails if it is not empty, which ends the pruning
+		for dir := filepath.Dir(file); isBelow(dir, output); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+}
+
+// isBelow checks whether the path lies within, but is not, the folder.
+func isBelow(path, folder string) bool {
+	relative, err := filepath.Rel(folder, path)
+
+	return err == nil && relative != "." && filepath.IsLocal(relative)
+}
+
+// Stage stages the manifest in the transaction, to be written on commit,
+// or deleted if no files are recorded.
+func (m Manifest) Stage(tx *file.Transaction) error {
+	if len(m.Files) == 0 {
+		tx.Remove(file.File(m.path))
+
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("creating manifest folder: %w", err)
+	}
+
+	if err := tx.Write(file.File(m.path), data, 0o644); err != nil {
+		return fmt.Errorf("writing manifest %q: %w", m.path, err)
+	}
+
+	return nil
+}
+
+// Save writes the manifest, or deletes it if no files are recorded.
+func (m Manifest) Save() error {
+	var tx file.Transaction
+	defer tx.Discard()
+
+	if err := m.Stage(&tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("saving manifest %q: %w", m.path, err)
+	}
+
+	return nil
+}
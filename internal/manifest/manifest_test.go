@@ -0,0 +1 @@
+package manifest_test
@@ -1,7 +1,6 @@
 package match
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/idelchi/godyl/internal/detect"
@@ -27,105 +26,143 @@ func (a *Asset) Parse() {
 }
 
 // MatchHint checks if the asset's name matches the provided hint.
-// The hint can be a regular expression or a simple substring match.
+// The hint can be a regular expression or a glob pattern.
 func (a Asset) MatchHint(hint Hint) bool {
-	regex, err := regexp.Compile(hint.Pattern)
-	return err == nil && regex.MatchString(a.NameLower())
+	return hint.Matches(a.NameLower())
 }
 
 // PlatformMatch evaluates whether the asset's platform matches the required platform.
 // It calculates a score based on the degree of compatibility and returns whether the asset is qualified.
 func (a Asset) PlatformMatch(req Requirements) (int, bool) {
-	var score int
+	var evaluation Evaluation
 
-	qualified := true
+	a.evaluatePlatform(req, &evaluation)
 
-	// fmt.Printf("Checking asset %q\n", a.Name)
+	return evaluation.Platform.Score(), len(evaluation.Rejections) == 0
+}
+
+// evaluatePlatform scores the asset's platform against the required platform,
+// recording the contribution of each criterion and the reasons for disqualification.
+func (a Asset) evaluatePlatform(req Requirements, e *Evaluation) {
+	required, actual := req.Platform, a.Platform
 
 	// Match operating system
-	if req.Platform.OS.Is(a.Platform.OS) {
-		// fmt.Printf("OS %q matches OS %q\n", req.Platform.OS, a.Platform.OS)
+	if required.OS.Is(actual.OS) {
+		e.Platform.Add("os", 1)
+	}
 
-		score++
+	if required.OS.IsCompatibleWith(actual.OS) {
+		e.Platform.Add("os compatible", 1)
+	} else if !actual.OS.IsUnset() && !required.OS.IsUnset() {
+		e.Reject("os %q is not compatible with %q", actual.OS, required.OS)
 	}
 
-	if req.Platform.OS.IsCompatibleWith(a.Platform.OS) {
-		// fmt.Printf("OS %q is compatible with OS %q\n", req.Platform.OS, a.Platform.OS)
+	// Match architecture
+	if required.Architecture.Is(actual.Architecture) {
+		e.Platform.Add("arch", 1)
+	}
 
-		score++
-	} else if !a.Platform.OS.IsUnset() && !req.Platform.OS.IsUnset() {
-		// fmt.Printf("OS %q is not compatible with OS %q\n", req.Platform.OS, a.Platform.OS)
+	switch {
+	case required.Architecture.IsCompatibleWith(actual.Architecture):
+		e.Platform.Add("arch compatible", 1)
 
-		qualified = false
-	} else {
-		// score--
+		// Prefer the highest x86-64 microarchitecture level supported
+		if actual.Architecture.Type == "amd64" && actual.Architecture.Version != 0 {
+			e.Platform.Add("arch level", actual.Architecture.Version)
+		}
+	case a.IsEmulated(req):
+		// Emulated builds rank below native builds and builds without a detected architecture
+		e.Platform.Add("arch emulated", -2)
+		e.Emulated = true
+	case !actual.Architecture.IsUnset() && !required.Architecture.IsUnset():
+		e.Reject("arch %q is not compatible with %q", actual.Architecture, required.Architecture)
+	default:
+		e.Platform.Add("arch unknown", -1)
 	}
 
-	if req.Platform.Architecture.Is(a.Platform.Architecture) {
-		// fmt.Printf("Architecture %q matches Architecture %q\n", req.Platform.Architecture, a.Platform.Architecture)
+	// Match library
+	if required.Library.Is(actual.Library) {
+		e.Platform.Add("library", 1)
+	}
 
-		score++
+	if required.Library.IsCompatibleWith(actual.Library) {
+		e.Platform.Add("library compatible", 1)
+	} else if !actual.Library.IsUnset() && !required.Library.IsUnset() {
+		e.Reject("library %q is not compatible with %q", actual.Library, required.Library)
 	}
+}
 
-	if req.Platform.Architecture.IsCompatibleWith(a.Platform.Architecture) {
-		// fmt.Printf("Architecture %q is compatible with Architecture %q\n", req.Platform.Architecture,
-		// a.Platform.Architecture)
+// IsEmulated checks whether the asset's architecture is not natively supported by the required platform,
+// but can be run through emulation.
+func (a Asset) IsEmulated(req Requirements) bool {
+	if a.Platform.Architecture.IsUnset() || req.Platform.Architecture.IsCompatibleWith(a.Platform.Architecture) {
+		return false
+	}
 
-		score++
-	} else if !a.Platform.Architecture.IsUnset() && !req.Platform.Architecture.IsUnset() {
-		// fmt.Printf("Architecture %q is not compatible with Architecture %q\n", req.Platform.Architecture,
-		// a.Platform.Architecture)
+	for _, emulated := range req.Emulated {
+		if emulated.IsCompatibleWith(a.Platform.Architecture) {
+			return true
+		}
+	}
 
-		qualified = false
-	} else {
-		// fmt.Printf("Architecture %q is not compatible with Architecture %q\n", req.Platform.Architecture,
-		// a.Platform.Architecture)
+	return false
+}
 
-		score--
-	}
+// Evaluate scores the asset against the requirements, recording the contribution
+// of each platform criterion and each hint, as well as the reasons for disqualification.
+func (a Asset) Evaluate(req Requirements) Evaluation {
+	evaluation := Evaluation{Asset: a}
 
-	if req.Platform.Library.Is(a.Platform.Library) {
-		// fmt.Printf("Library %q matches Library %q\n", req.Platform.Library, a.Platform.Library)
+	a.evaluatePlatform(req, &evaluation)
 
-		score++
-	}
+	var mustFailed bool
 
-	if req.Platform.Library.IsCompatibleWith(a.Platform.Library) {
-		// fmt.Printf("Library %q is compatible with Library %q\n", req.Platform.Library, a.Platform.Library)
+	for _, hint := range req.Hints {
+		// Content hints can only be evaluated once the asset is downloaded
+		if hint.Content {
+			evaluation.Hints.Add(hint.Pattern, 0)
 
-		score++
-	} else if !a.Platform.Library.IsUnset() && !req.Platform.Library.IsUnset() {
-		// fmt.Printf("Library %q is not compatible with Library %q\n", req.Platform.Library, a.Platform.Library)
+			continue
+		}
 
-		qualified = false
-	} else {
-		// fmt.Printf("Library %q is not compatible with Library %q\n", req.Platform.Library, a.Platform.Library)
+		matched := a.MatchHint(hint)
+
+		switch {
+		case hint.MustNot && matched:
+			evaluation.Reject("must-not hint %q matched", hint.Pattern)
+			evaluation.Hints.Add(hint.Pattern, 0)
+
+			mustFailed = true
+		case hint.MustNot:
+			evaluation.Hints.Add(hint.Pattern, 0)
+		case hint.Must && !matched:
+			evaluation.Reject("must hint %q not matched", hint.Pattern)
+			evaluation.Hints.Add(hint.Pattern, 0)
+
+			mustFailed = true
+		case hint.Must:
+			evaluation.Hints.Add(hint.Pattern, 0)
+		case matched:
+			evaluation.Hints.Add(hint.Pattern, hint.GetWeight())
+		default:
+			evaluation.Hints.Add(hint.Pattern, 0)
+		}
+	}
 
-		// score--
+	evaluation.Qualified = len(evaluation.Rejections) == 0
+
+	// Assets failing a mandatory or excluding hint are not scored at all
+	if !mustFailed {
+		evaluation.Score = evaluation.Platform.Score() + evaluation.Hints.Score()
 	}
 
-	return score, qualified
+	return evaluation
 }
 
 // Match evaluates if the asset satisfies the given requirements.
 // It aggregates scores from both platform compatibility and matching hints.
 func (a Asset) Match(req Requirements) (int, bool) {
-	// Check mandatory hints
-	for _, hint := range req.Hints {
-		if hint.Must && !a.MatchHint(hint) {
-			return 0, false
-		}
-	}
-
-	// Match platform requirements
-	score, qualified := a.PlatformMatch(req)
-
-	// Check non-mandatory hints and adjust the score
-	for _, hint := range req.Hints {
-		if !hint.Must && a.MatchHint(hint) {
-			score += hint.GetWeight()
-		}
-	}
+	evaluation := a.Evaluate(req)
 
-	return score, qualified
+	return evaluation.Score, evaluation.Qualified
 }
@@ -30,11 +30,16 @@ func (as Assets) Select(req Requirements) Results {
 // Match evaluates all assets against the provided requirements and returns a list of results.
 // Each result contains the asset's name, its matching score, and whether it qualifies.
 func (as Assets) Match(req Requirements) Results {
-	var results Results
+	return as.Evaluate(req).Results()
+}
+
+// Evaluate scores all assets against the provided requirements,
+// explaining the contribution of each platform criterion and hint.
+func (as Assets) Evaluate(req Requirements) Evaluations {
+	var evaluations Evaluations
 	for _, a := range as {
-		score, qualified := a.Match(req)
-		results = append(results, Result{Asset: a, Score: score, Qualified: qualified})
+		evaluations = append(evaluations, a.Evaluate(req))
 	}
 
-	return results
+	return evaluations
 }
@@ -0,0 +1,70 @@
+package match
+
+import (
+	"fmt"
+)
+
+// Contribution is the score contributed by a single criterion, such as a platform property or a hint.
+type Contribution struct {
+	Criterion string `json:"criterion"`
+	Score     int    `json:"score"`
+}
+
+// Contributions is a collection of Contribution objects.
+type Contributions []Contribution
+
+// Add records the score contributed by a criterion.
+func (c *Contributions) Add(criterion string, score int) {
+	*c = append(*c, Contribution{Criterion: criterion, Score: score})
+}
+
+// Score returns the sum of all contributions.
+func (c Contributions) Score() (score int) {
+	for _, contribution := range c {
+		score += contribution.Score
+	}
+
+	return score
+}
+
+// Evaluation explains how an asset was scored against a set of requirements.
+type Evaluation struct {
+	Asset      Asset         `json:"asset"`      // Asset being evaluated.
+	Platform   Contributions `json:"platform"`   // Platform lists the score contributed by each platform criterion.
+	Hints      Contributions `json:"hints"`      // Hints lists the score contributed by each hint, in order.
+	Rejections []string      `json:"rejections"` // Rejections lists the reasons the asset was disqualified.
+	Score      int           `json:"score"`      // Score is the total score of the asset.
+	Qualified  bool          `json:"qualified"`  // Qualified indicates whether the asset meets the necessary criteria.
+	Emulated   bool          `json:"emulated"`   // Emulated indicates whether the asset can only be run through emulation.
+}
+
+// Reject records a reason for disqualifying the asset.
+func (e *Evaluation) Reject(format string, args ...any) {
+	e.Rejections = append(e.Rejections, fmt.Sprintf(format, args...))
+}
+
+// Result returns the outcome of the evaluation.
+func (e Evaluation) Result() Result {
+	return Result{Asset: e.Asset, Score: e.Score, Qualified: e.Qualified, Emulated: e.Emulated}
+}
+
+// Evaluations is a collection of Evaluation objects.
+type Evaluations []Evaluation
+
+// Results returns the outcome of each evaluation.
+func (e Evaluations) Results() Results {
+	results := make(Results, 0, len(e))
+
+	for _, evaluation := range e {
+		results = append(results, evaluation.Result())
+	}
+
+	return results
+}
+
+// Explanation describes how an asset was selected among a set of assets.
+type Explanation struct {
+	Requirements Requirements `json:"requirements"` // Requirements the assets were evaluated against.
+	Evaluations  Evaluations  `json:"assets"`       // Evaluations of all assets.
+	Winner       string       `json:"winner"`       // Winner is the name of the selected asset, if any.
+}
@@ -1,13 +1,36 @@
 package match
 
-import "strconv"
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrHint is returned when a hint is not valid.
+var ErrHint = errors.New("invalid hint")
+
+// HintType defines the syntax of a hint's pattern.
+type HintType string
+
+const (
+	// Regex patterns are regular expressions, and match anywhere in the name.
+	Regex HintType = "regex"
+	// Glob patterns are shell patterns, and must match the whole name.
+	Glob HintType = "glob"
+)
 
 // Hint represents a pattern used to match asset names.
 // It can be a regular expression or a simple string pattern.
 type Hint struct {
-	Pattern string // Pattern to match against the asset's name.
-	Weight  string // Weight used to adjust the score for non-mandatory hints.
-	Must    bool   // Indicates if the hint is mandatory for a match.
+	Pattern string   // Pattern to match against the asset's name.
+	Weight  string   // Weight used to adjust the score for non-mandatory hints.
+	Must    bool     // Indicates if the hint is mandatory for a match.
+	MustNot bool     `mapstructure:"must-not" yaml:"must-not"` // Indicates if matching assets are excluded.
+	Type    HintType // Type is the syntax of the pattern, defaulting to Regex.
+	Content bool     // Indicates if the hint matches the files inside the downloaded asset instead of its name.
 
 	weightInt int `json:"-" mapstructure:"-" yaml:"-"`
 }
@@ -28,3 +51,46 @@ func (h *Hint) SetWeight() error {
 func (h Hint) GetWeight() int {
 	return h.weightInt
 }
+
+// Validate checks that the hint's type is known, its pattern compiles and that
+// it is not both mandatory and excluding.
+func (h Hint) Validate() error {
+	if h.Must && h.MustNot {
+		return fmt.Errorf("%w: %q: `must` and `must-not` are mutually exclusive", ErrHint, h.Pattern)
+	}
+
+	switch h.Type {
+	case "", Regex:
+		if _, err := regexp.Compile(h.Pattern); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrHint, h.Pattern, err)
+		}
+	case Glob:
+		if _, err := path.Match(h.Pattern, ""); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrHint, h.Pattern, err)
+		}
+	default:
+		return fmt.Errorf("%w: %q: unknown type %q, allowed are %q and %q", ErrHint, h.Pattern, h.Type, Regex, Glob)
+	}
+
+	return nil
+}
+
+// Matches checks whether the name matches the hint's pattern.
+// Glob patterns without a slash are matched against the last element of the name only,
+// allowing `bin/tool` to be matched by `tool`.
+func (h Hint) Matches(name string) bool {
+	switch h.Type {
+	case Glob:
+		if !strings.Contains(h.Pattern, "/") {
+			name = path.Base(name)
+		}
+
+		matched, err := path.Match(h.Pattern, name)
+
+		return err == nil && matched
+	default:
+		regex, err := regexp.Compile(h.Pattern)
+
+		return err == nil && regex.MatchString(name)
+	}
+}
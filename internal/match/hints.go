@@ -1,5 +1,14 @@
 package match
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrContent is returned when the contents of a downloaded asset do not satisfy the content hints.
+var ErrContent = errors.New("asset contents do not match")
+
 // Hints represents a collection of Hint objects used to evaluate asset matches.
 type Hints []Hint
 
@@ -12,3 +21,37 @@ func (h *Hints) Append(hints Hints) {
 func (h *Hints) Add(hints ...Hint) {
 	h.Append(hints)
 }
+
+// Content returns the hints which match against the files inside downloaded assets.
+func (h Hints) Content() Hints {
+	var content Hints
+
+	for _, hint := range h {
+		if hint.Content {
+			content = append(content, hint)
+		}
+	}
+
+	return content
+}
+
+// MatchContent checks the files inside a downloaded asset against the content hints.
+// Content hints are requirements: the asset must contain a file matching each of them,
+// or none for `must-not` hints. Hints matching asset names are ignored.
+// It returns an error wrapping ErrContent listing the unsatisfied hints.
+func (h Hints) MatchContent(files []string) error {
+	var errs []error
+
+	for _, hint := range h.Content() {
+		matched := slices.ContainsFunc(files, hint.Matches)
+
+		switch {
+		case hint.MustNot && matched:
+			errs = append(errs, fmt.Errorf("%w: contains a file matching %q", ErrContent, hint.Pattern))
+		case !hint.MustNot && !matched:
+			errs = append(errs, fmt.Errorf("%w: contains no file matching %q", ErrContent, hint.Pattern))
+		}
+	}
+
+	return errors.Join(errs...)
+}
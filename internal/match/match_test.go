@@ -1 +1,360 @@
 package match_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/internal/match"
+)
+
+// releases mimics the assets published for a Go release, covering the less common platforms.
+var releases = []string{
+	"go1.23.3.linux-amd64.tar.gz",
+	"go1.23.3.linux-arm64.tar.gz",
+	"go1.23.3.linux-armv6l.tar.gz",
+	"go1.23.3.linux-riscv64.tar.gz",
+	"go1.23.3.linux-loong64.tar.gz",
+	"go1.23.3.linux-ppc64le.tar.gz",
+	"go1.23.3.linux-ppc64.tar.gz",
+	"go1.23.3.linux-s390x.tar.gz",
+	"go1.23.3.linux-mips64le.tar.gz",
+	"go1.23.3.linux-mips64.tar.gz",
+	"go1.23.3.linux-mipsle.tar.gz",
+	"go1.23.3.linux-mips.tar.gz",
+	"go1.23.3.illumos-amd64.tar.gz",
+	"go1.23.3.solaris-amd64.tar.gz",
+	"go1.23.3.dragonfly-amd64.tar.gz",
+}
+
+func requirements(t *testing.T, name string) match.Requirements {
+	t.Helper()
+
+	var platform detect.Platform
+
+	platform.Parse(name)
+
+	return match.Requirements{Platform: platform}
+}
+
+func TestAssetParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		os   string
+		arch string
+	}{
+		{name: "ripgrep-14.1.1-riscv64gc-unknown-linux-gnu.tar.gz", os: "linux", arch: "riscv64"},
+		{name: "go1.23.3.linux-riscv64.tar.gz", os: "linux", arch: "riscv64"},
+		{name: "uv-loongarch64-unknown-linux-gnu.tar.gz", os: "linux", arch: "loong64"},
+		{name: "go1.23.3.linux-loong64.tar.gz", os: "linux", arch: "loong64"},
+		{name: "ripgrep-14.1.1-powerpc64le-unknown-linux-gnu.tar.gz", os: "linux", arch: "ppc64le"},
+		{name: "yq_linux_ppc64le.tar.gz", os: "linux", arch: "ppc64le"},
+		{name: "gitleaks_8.21.2_linux_ppc64el.deb", os: "linux", arch: "ppc64le"},
+		{name: "ripgrep-14.1.1-powerpc64-unknown-linux-gnu.tar.gz", os: "linux", arch: "ppc64"},
+		{name: "ripgrep-14.1.1-s390x-unknown-linux-gnu.tar.gz", os: "linux", arch: "s390x"},
+		{name: "k9s_Linux_s390x.tar.gz", os: "linux", arch: "s390x"},
+		{name: "yq_linux_mips64le.tar.gz", os: "linux", arch: "mips64le"},
+		{name: "task_linux_mips64el.deb", os: "linux", arch: "mips64le"},
+		{name: "yq_linux_mips64.tar.gz", os: "linux", arch: "mips64"},
+		{name: "yq_linux_mipsle.tar.gz", os: "linux", arch: "mipsle"},
+		{name: "hugo_0.139.0_linux-mipsel.tar.gz", os: "linux", arch: "mipsle"},
+		{name: "yq_linux_mips.tar.gz", os: "linux", arch: "mips"},
+		{name: "go1.23.3.illumos-amd64.tar.gz", os: "illumos", arch: "amd64"},
+		{name: "restic_0.17.3_solaris_amd64.bz2", os: "solaris", arch: "amd64"},
+		{name: "node-v22.11.0-sunos-x64.tar.xz", os: "solaris", arch: "amd64"},
+		{name: "go1.23.3.dragonfly-amd64.tar.gz", os: "dragonfly", arch: "amd64"},
+		{name: "rclone-v1.68.2-dragonflybsd-amd64.zip", os: "dragonfly", arch: "amd64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			asset := match.Asset{Name: tt.name}
+			asset.Parse()
+
+			if got := asset.Platform.OS.Type; got != tt.os {
+				t.Errorf("OS = %q, want %q", got, tt.os)
+			}
+
+			if got := asset.Platform.Architecture.Type; got != tt.arch {
+				t.Errorf("architecture = %q, want %q", got, tt.arch)
+			}
+		})
+	}
+}
+
+func TestAssetsSelect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		platform string
+		want     string
+	}{
+		{platform: "linux-riscv64", want: "go1.23.3.linux-riscv64.tar.gz"},
+		{platform: "linux-loongarch64", want: "go1.23.3.linux-loong64.tar.gz"},
+		{platform: "linux-ppc64le", want: "go1.23.3.linux-ppc64le.tar.gz"},
+		{platform: "linux-ppc64", want: "go1.23.3.linux-ppc64.tar.gz"},
+		{platform: "linux-s390x", want: "go1.23.3.linux-s390x.tar.gz"},
+		{platform: "linux-mips64el", want: "go1.23.3.linux-mips64le.tar.gz"},
+		{platform: "linux-mips64", want: "go1.23.3.linux-mips64.tar.gz"},
+		{platform: "linux-mipsel", want: "go1.23.3.linux-mipsle.tar.gz"},
+		{platform: "linux-mips", want: "go1.23.3.linux-mips.tar.gz"},
+		{platform: "illumos-amd64", want: "go1.23.3.illumos-amd64.tar.gz"},
+		{platform: "solaris-amd64", want: "go1.23.3.solaris-amd64.tar.gz"},
+		{platform: "dragonfly-amd64", want: "go1.23.3.dragonfly-amd64.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			assets := match.Assets{}.FromNames(releases...)
+			for i := range assets {
+				assets[i].Parse()
+			}
+
+			results := assets.Select(requirements(t, tt.platform))
+			if err := results.Status(); err != nil {
+				t.Fatalf("selecting asset for %q: %v", tt.platform, err)
+			}
+
+			if got := results[0].Asset.Name; got != tt.want {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetsSelectIncompatible(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		platform string
+		assets   []string
+	}{
+		{
+			platform: "linux-ppc64le",
+			assets:   []string{"go1.23.3.linux-ppc64.tar.gz"},
+		},
+		{
+			platform: "linux-mips64le",
+			assets:   []string{"go1.23.3.linux-mips64.tar.gz", "go1.23.3.linux-mipsle.tar.gz"},
+		},
+		{
+			platform: "linux-riscv64",
+			assets:   []string{"go1.23.3.linux-arm64.tar.gz", "go1.23.3.linux-amd64.tar.gz"},
+		},
+		{
+			platform: "solaris-amd64",
+			assets:   []string{"go1.23.3.illumos-amd64.tar.gz"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			assets := match.Assets{}.FromNames(tt.assets...)
+			for i := range assets {
+				assets[i].Parse()
+			}
+
+			if results := assets.Select(requirements(t, tt.platform)); results.HasQualified() {
+				t.Errorf("expected no qualified assets for %q, got %q", tt.platform, results[0].Asset.Name)
+			}
+		})
+	}
+}
+
+func TestAssetsSelectIllumosAcceptsSolaris(t *testing.T) {
+	t.Parallel()
+
+	assets := match.Assets{}.FromNames("restic_0.17.3_solaris_amd64.bz2", "restic_0.17.3_linux_amd64.bz2")
+	for i := range assets {
+		assets[i].Parse()
+	}
+
+	results := assets.Select(requirements(t, "illumos-amd64"))
+	if err := results.Status(); err != nil {
+		t.Fatalf("selecting asset: %v", err)
+	}
+
+	if got, want := results[0].Asset.Name, "restic_0.17.3_solaris_amd64.bz2"; got != want {
+		t.Errorf("selected %q, want %q", got, want)
+	}
+}
+
+func TestAssetsSelectHints(t *testing.T) {
+	t.Parallel()
+
+	names := []string{
+		"tool_1.0.0_linux_amd64.tar.gz",
+		"tool_1.0.0_linux_amd64.tar.gz.sbom",
+		"tool_1.0.0_linux_amd64.tar.gz.sig",
+		"tool-debug_1.0.0_linux_amd64.tar.gz",
+	}
+
+	tests := []struct {
+		name  string
+		hints []match.Hint
+		want  string
+	}{
+		{
+			name: "must-not regex",
+			hints: []match.Hint{
+				{Pattern: `\.(sbom|sig)$`, MustNot: true},
+				{Pattern: `-debug`, MustNot: true},
+			},
+			want: "tool_1.0.0_linux_amd64.tar.gz",
+		},
+		{
+			name: "must glob",
+			hints: []match.Hint{
+				{Pattern: "tool-debug_*.tar.gz", Type: match.Glob, Must: true},
+			},
+			want: "tool-debug_1.0.0_linux_amd64.tar.gz",
+		},
+		{
+			name: "must-not glob",
+			hints: []match.Hint{
+				{Pattern: "*.s*", Type: match.Glob, MustNot: true},
+				{Pattern: "*-debug_*", Type: match.Glob, MustNot: true},
+			},
+			want: "tool_1.0.0_linux_amd64.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assets := match.Assets{}.FromNames(names...)
+			for i := range assets {
+				assets[i].Parse()
+			}
+
+			req := requirements(t, "linux-amd64")
+			req.Hints = tt.hints
+
+			results := assets.Select(req)
+			if err := results.Status(); err != nil {
+				t.Fatalf("selecting asset: %v", err)
+			}
+
+			if got := results[0].Asset.Name; got != tt.want {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintsMatchContent(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"tool-1.0.0/bin/tool", "tool-1.0.0/README.md"}
+
+	tests := []struct {
+		name  string
+		hint  match.Hint
+		valid bool
+	}{
+		{name: "glob base name", hint: match.Hint{Pattern: "tool", Type: match.Glob, Content: true}, valid: true},
+		{name: "glob path", hint: match.Hint{Pattern: "*/bin/tool", Type: match.Glob, Content: true}, valid: true},
+		{name: "regex", hint: match.Hint{Pattern: `bin/tool$`, Content: true}, valid: true},
+		{name: "missing", hint: match.Hint{Pattern: "tool.exe", Type: match.Glob, Content: true}, valid: false},
+		{name: "must-not", hint: match.Hint{Pattern: "*.md", Type: match.Glob, MustNot: true, Content: true}, valid: false},
+		{name: "name hint ignored", hint: match.Hint{Pattern: "missing", Must: true}, valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := match.Hints{tt.hint}.MatchContent(files)
+
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !tt.valid && !errors.Is(err, match.ErrContent):
+				t.Errorf("expected %v, got %v", match.ErrContent, err)
+			}
+		})
+	}
+}
+
+func TestAssetsSelectEmulated(t *testing.T) {
+	t.Parallel()
+
+	var amd64 platform.Architecture
+	if err := amd64.Parse("amd64"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		assets    []string
+		emulated  bool
+		want      string
+		qualified bool
+	}{
+		{
+			name:      "native preferred",
+			assets:    []string{"tool_darwin_amd64.tar.gz", "tool_darwin_arm64.tar.gz"},
+			emulated:  true,
+			want:      "tool_darwin_arm64.tar.gz",
+			qualified: true,
+		},
+		{
+			name:      "emulated only",
+			assets:    []string{"tool_darwin_amd64.tar.gz"},
+			emulated:  true,
+			want:      "tool_darwin_amd64.tar.gz",
+			qualified: true,
+		},
+		{
+			name:      "not emulated",
+			assets:    []string{"tool_darwin_amd64.tar.gz"},
+			emulated:  false,
+			want:      "tool_darwin_amd64.tar.gz",
+			qualified: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assets := match.Assets{}.FromNames(tt.assets...)
+			for i := range assets {
+				assets[i].Parse()
+			}
+
+			req := requirements(t, "darwin-arm64")
+			if tt.emulated {
+				req.Emulated = []platform.Architecture{amd64}
+			}
+
+			results := assets.Select(req)
+
+			if got := results[0]; got.Asset.Name != tt.want || got.Qualified != tt.qualified {
+				t.Fatalf("selected %q (qualified %t), want %q (qualified %t)", got.Asset.Name, got.Qualified, tt.want, tt.qualified)
+			}
+
+			for _, result := range results {
+				emulated := tt.emulated && result.Asset.Platform.Architecture.Type == "amd64"
+				if result.Emulated != emulated {
+					t.Errorf("%q: emulated = %t, want %t", result.Asset.Name, result.Emulated, emulated)
+				}
+			}
+
+			if len(results) > 1 && results[1].Score >= results[0].Score {
+				t.Errorf("emulated %q scored %d, want below native %q with %d",
+					results[1].Asset.Name, results[1].Score, results[0].Asset.Name, results[0].Score)
+			}
+		})
+	}
+}
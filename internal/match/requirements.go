@@ -2,11 +2,18 @@ package match
 
 import (
 	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
 )
 
 // Requirements represents the criteria an asset must meet.
 // It includes platform compatibility and a list of hints for name matching.
 type Requirements struct {
-	Platform detect.Platform // Platform specifies the required OS, architecture, and library compatibility.
-	Hints    []Hint          // Hints contains patterns used to match the asset's name.
+	Platform detect.Platform         // Platform specifies the required OS, architecture, and library compatibility.
+	Hints    []Hint                  // Hints contains patterns used to match the asset's name.
+	Emulated []platform.Architecture // Emulated lists architectures the platform can run through emulation.
+	Chooser  Chooser                 `json:"-"` // Chooser resolves ambiguous matches, if set.
 }
+
+// Chooser picks one of several equally good results.
+// It should return an error wrapping ErrAmbiguous if no choice was made.
+type Chooser func(candidates Results) (Result, error)
@@ -21,6 +21,7 @@ type Result struct {
 	Asset     Asset // Asset being evaluated.
 	Score     int   // Score representing how well the asset matches the requirements.
 	Qualified bool  // Qualified indicates whether the asset meets the necessary criteria.
+	Emulated  bool  // Emulated indicates whether the asset can only be run through emulation.
 }
 
 // Results is a collection of Result objects.
@@ -33,6 +34,9 @@ func (m Results) ToString() string {
 		result += fmt.Sprintf("	- %s\n", r.Asset.Name)
 		result += fmt.Sprintf("		score: %d\n", r.Score)
 		result += fmt.Sprintf("		qualified: %t\n", r.Qualified)
+		if r.Emulated {
+			result += "		emulated: true\n"
+		}
 		result += "		detected as:\n"
 		result += fmt.Sprintf("		  os: %s\n", r.Asset.Platform.OS)
 		result += fmt.Sprintf("		  arch: %s\n", r.Asset.Platform.Architecture)
@@ -0,0 +1,3 @@
+// Package report records the outcome of each tool in a run,
+// and writes it in machine-readable formats for CI dashboards and pull request comments.
+package report
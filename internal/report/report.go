@@ -0,0 +1,287 @@
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a tool.
+type Status string
+
+const (
+	// Installed indicates that the tool was newly installed.
+	Installed Status = "installed"
+	// Upgraded indicates that a previously installed tool was replaced by another version.
+	Upgraded Status = "upgraded"
+	// Reinstalled indicates that a previously installed tool was replaced by the same version, or one not known to differ.
+	Reinstalled Status = "reinstalled"
+	// UpToDate indicates that the installed tool already has the requested version.
+	UpToDate Status = "up-to-date"
+	// Skipped indicates that the tool was not installed, e.g. as it already exists or by its skip conditions.
+	Skipped Status = "skipped"
+	// Filtered indicates that the tool was excluded by the tags.
+	Filtered Status = "filtered"
+	// Failed indicates that the tool could not be installed.
+	Failed Status = "failed"
+)
+
+// Format is the format in which a report is written.
+type Format string
+
+const (
+	// JSON writes the report as a JSON document.
+	JSON Format = "json"
+	// JUnit writes the report as JUnit XML, with a test case per tool.
+	JUnit Format = "junit"
+	// Markdown writes the report as a Markdown table.
+	Markdown Format = "markdown"
+)
+
+// Formats returns the supported formats.
+func Formats() []Format {
+	return []Format{JSON, JUnit, Markdown}
+}
+
+// Entry is the outcome of a single tool.
+type Entry struct {
+	// Tool is the name of the tool.
+	Tool string `json:"tool"`
+	// Status is the outcome of the tool.
+	Status Status `json:"status"`
+	// VersionBefore is the version installed before the run, if known.
+	VersionBefore string `json:"version_before,omitempty"`
+	// VersionAfter is the version installed (or resolved) by the run.
+	VersionAfter string `json:"version_after,omitempty"`
+	// Asset is the URL or path the tool was downloaded from.
+	Asset string `json:"asset,omitempty"`
+	// Duration is the time spent on the tool.
+	Duration time.Duration `json:"-"`
+	// Category classifies the reason for a status other than installed, upgraded or reinstalled, such as the failed phase.
+	Category string `json:"category,omitempty"`
+	// Error is the message of the error causing the status.
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalJSON writes the duration in seconds.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	type raw Entry
+
+	return json.Marshal(struct {
+		raw
+		Duration float64 `json:"duration"`
+	}{raw(e), e.Duration.Seconds()})
+}
+
+// Report holds the outcomes of all tools in a run.
+type Report struct {
+	// Started is the time the run started.
+	Started time.Time `json:"started"`
+	// Entries holds the outcome of each tool.
+	Entries []Entry `json:"tools"`
+}
+
+// New creates a report of a run starting now.
+func New() *Report {
+	return &Report{Started: time.Now()}
+}
+
+// Count returns the number of entries with the given status.
+func (r *Report) Count(status Status) int {
+	count := 0
+
+	for _, entry := range r.Entries {
+		if entry.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Write writes the report in the given format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case JSON:
+		return r.writeJSON(w)
+	case JUnit:
+		return r.writeJUnit(w)
+	case Markdown:
+		return r.writeMarkdown(w)
+	default:
+		return fmt.Errorf("unknown report format %q: allowed are %v", format, Formats())
+	}
+}
+
+// writeJSON writes the report as an indented JSON document, including the counts per status.
+func (r *Report) writeJSON(w io.Writer) error {
+	counts := make(map[Status]int)
+	for _, entry := range r.Entries {
+		counts[entry.Status]++
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(struct {
+		*Report
+		Duration float64        `json:"duration"`
+		Counts   map[Status]int `json:"counts"`
+	}{r, time.Since(r.Started).Seconds(), counts})
+}
+
+// junitSuite is a JUnit test suite, holding a test case per tool.
+type junitSuite struct {
+	XMLName   xml.Name    `xml:"testsuite"`
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      float64     `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+// junitCase is a JUnit test case, failed or skipped according to the outcome of the tool.
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Output    string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the reason for a failed or skipped test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the report as a JUnit test suite.
+// Failed tools are failures, and tools which were skipped, filtered or up to date are skipped test cases.
+func (r *Report) writeJUnit(w io.Writer) error {
+	suite := junitSuite{
+		Name:      "godyl",
+		Tests:     len(r.Entries),
+		Time:      time.Since(r.Started).Seconds(),
+		Timestamp: r.Started.Format(time.RFC3339),
+	}
+
+	for _, entry := range r.Entries {
+		test := junitCase{
+			Name:      entry.Tool,
+			ClassName: "godyl",
+			Time:      entry.Duration.Seconds(),
+			Output:    entry.summary(),
+		}
+
+		message := &junitMessage{Message: entry.Error, Type: entry.Category, Text: entry.Error}
+
+		switch entry.Status {
+		case Failed:
+			suite.Failures++
+			test.Failure = message
+		case Skipped, Filtered, UpToDate:
+			suite.Skipped++
+			message.Message = cmp.Or(entry.Error, string(entry.Status))
+			test.Skipped = message
+		}
+
+		suite.Cases = append(suite.Cases, test)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// writeMarkdown writes the report as a Markdown table, preceded by the counts per status.
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	counts := []string{}
+
+	for _, status := range []Status{Installed, Upgraded, Reinstalled, UpToDate, Skipped, Filtered, Failed} {
+		if count := r.Count(status); count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count, status))
+		}
+	}
+
+	fmt.Fprintf(&b, "### godyl: %d tools\n\n", len(r.Entries))
+
+	if len(counts) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(counts, ", "))
+	}
+
+	b.WriteString("| Tool | Status | Version | Asset | Duration | Error |\n")
+	b.WriteString("| ---- | ------ | ------- | ----- | -------- | ----- |\n")
+
+	for _, entry := range r.Entries {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(entry.Tool),
+			markdownCell(string(entry.Status)),
+			markdownCell(entry.version()),
+			markdownCell(entry.Asset),
+			entry.Duration.Round(time.Millisecond),
+			markdownCell(strings.Join(slices.DeleteFunc([]string{entry.Category, entry.Error}, isEmpty), ": ")),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// version describes the version change of the entry, ignoring a "v" prefix only one of the versions has.
+func (e Entry) version() string {
+	if e.VersionBefore != "" && strings.TrimPrefix(e.VersionBefore, "v") != strings.TrimPrefix(e.VersionAfter, "v") {
+		return e.VersionBefore + " → " + e.VersionAfter
+	}
+
+	return e.VersionAfter
+}
+
+// summary describes the entry on a single line.
+func (e Entry) summary() string {
+	parts := []string{string(e.Status)}
+
+	if version := e.version(); version != "" {
+		parts = append(parts, version)
+	}
+
+	if e.Asset != "" {
+		parts = append(parts, e.Asset)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// markdownCell escapes the text for a table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isEmpty checks whether the string is empty.
+func isEmpty(s string) bool {
+	return s == ""
+}
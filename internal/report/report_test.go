@@ -0,0 +1,182 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idelchi/godyl/internal/report"
+)
+
+// sample returns a report with an entry per kind of outcome.
+func sample() *report.Report {
+	r := report.New()
+
+	r.Entries = []report.Entry{
+		{Tool: "installed", Status: report.Installed, VersionAfter: "v1.0.0", Asset: "a.tar.gz", Duration: 1500 * time.Millisecond},
+		{Tool: "upgraded", Status: report.Upgraded, VersionBefore: "v1.0.0", VersionAfter: "v2.0.0"},
+		{Tool: "reinstalled", Status: report.Reinstalled, VersionBefore: "2.0.0", VersionAfter: "v2.0.0"},
+		{Tool: "current", Status: report.UpToDate, VersionBefore: "v1.0.0", VersionAfter: "v1.0.0"},
+		{Tool: "skipped", Status: report.Skipped, Category: "dependency", Error: "dependency failed"},
+		{Tool: "filtered", Status: report.Filtered, Category: "tags"},
+		{Tool: "failed", Status: report.Failed, Category: "install", Error: "no | match\nfound"},
+	}
+
+	return r
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := sample().Write(&buf, report.JSON); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var got struct {
+		Counts map[report.Status]int `json:"counts"`
+		Tools  []map[string]any      `json:"tools"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	for status, want := range map[report.Status]int{
+		report.Installed: 1, report.Upgraded: 1, report.Reinstalled: 1, report.UpToDate: 1, report.Skipped: 1, report.Filtered: 1, report.Failed: 1,
+	} {
+		if got.Counts[status] != want {
+			t.Errorf("counts[%s] = %d, want %d", status, got.Counts[status], want)
+		}
+	}
+
+	if len(got.Tools) != 7 {
+		t.Fatalf("got %d tools, want 7", len(got.Tools))
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{key: "tool", want: "installed"},
+		{key: "status", want: "installed"},
+		{key: "version_after", want: "v1.0.0"},
+		{key: "asset", want: "a.tar.gz"},
+		{key: "duration", want: 1.5},
+	}
+
+	for _, tt := range tests {
+		if got := got.Tools[0][tt.key]; got != tt.want {
+			t.Errorf("tools[0][%q] = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if _, ok := got.Tools[0]["error"]; ok {
+		t.Errorf("tools[0] has an error, want it omitted")
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := sample().Write(&buf, report.JUnit); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("missing XML header:\n%s", buf.String())
+	}
+
+	type message struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+	}
+
+	var got struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Cases    []struct {
+			Name    string   `xml:"name,attr"`
+			Failure *message `xml:"failure"`
+			Skipped *message `xml:"skipped"`
+		} `xml:"testcase"`
+	}
+
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	if got.Tests != 7 || got.Failures != 1 || got.Skipped != 3 {
+		t.Errorf("tests, failures, skipped = %d, %d, %d, want 7, 1, 3", got.Tests, got.Failures, got.Skipped)
+	}
+
+	tests := []struct {
+		name    string
+		failure string
+		skipped string
+	}{
+		{name: "installed"},
+		{name: "upgraded"},
+		{name: "reinstalled"},
+		{name: "current", skipped: "up-to-date"},
+		{name: "skipped", skipped: "dependency failed"},
+		{name: "filtered", skipped: "filtered"},
+		{name: "failed", failure: "no | match\nfound"},
+	}
+
+	for i, tt := range tests {
+		c := got.Cases[i]
+
+		if c.Name != tt.name {
+			t.Errorf("case %d: name = %q, want %q", i, c.Name, tt.name)
+		}
+
+		if (c.Failure != nil) != (tt.failure != "") || c.Failure != nil && c.Failure.Message != tt.failure {
+			t.Errorf("%s: failure = %+v, want %q", tt.name, c.Failure, tt.failure)
+		}
+
+		if (c.Skipped != nil) != (tt.skipped != "") || c.Skipped != nil && c.Skipped.Message != tt.skipped {
+			t.Errorf("%s: skipped = %+v, want %q", tt.name, c.Skipped, tt.skipped)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := sample().Write(&buf, report.Markdown); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got := buf.String()
+
+	for _, want := range []string{
+		"### godyl: 7 tools\n\n",
+		"1 installed, 1 upgraded, 1 reinstalled, 1 up-to-date, 1 skipped, 1 filtered, 1 failed\n\n",
+		"| Tool | Status | Version | Asset | Duration | Error |\n",
+		"| installed | installed | v1.0.0 | a.tar.gz | 1.5s |  |\n",
+		"| upgraded | upgraded | v1.0.0 → v2.0.0 |  | 0s |  |\n",
+		"| reinstalled | reinstalled | v2.0.0 |  | 0s |  |\n",
+		"| current | up-to-date | v1.0.0 |  | 0s |  |\n",
+		"| skipped | skipped |  |  | 0s | dependency: dependency failed |\n",
+		"| failed | failed |  |  | 0s | install: no \\| match found |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if err := sample().Write(&bytes.Buffer{}, "yaml"); err == nil {
+		t.Error("Write() error = nil, want an error for an unknown format")
+	}
+}
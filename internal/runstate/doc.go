@@ -0,0 +1,3 @@
+// Package runstate persists the inputs and the failed tools of the last run in the user cache folder,
+// so that only the failed tools can be retried.
+package runstate
@@ -0,0 +1,171 @@
+package runstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/idelchi/godyl/internal/detect"
+)
+
+// File is the name of the file holding the state of the last run, in the godyl folder of the user cache folder.
+const File = "last-run.json"
+
+// ErrNoState is returned when loading the state before any run has saved it.
+var ErrNoState = errors.New("no previous run found")
+
+// Tool identifies a failed tool by its position and name in the tools file.
+type Tool struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	// Version is the version resolved for the tool, if any, which is kept when retrying.
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// State holds the inputs of a run and the tools which failed in it, including those skipped as a dependency failed.
+type State struct {
+	Time time.Time `json:"time"`
+	// Tools is the tools file, or the name or URL of the tool, as given to the run.
+	Tools  string   `json:"tools"`
+	Tags   []string `json:"tags,omitempty"`
+	Output string   `json:"output"`
+	// Dir is the working folder of the run, against which relative paths resolve.
+	Dir      string          `json:"dir"`
+	Platform detect.Platform `json:"platform"`
+	Failed   []Tool          `json:"failed"`
+}
+
+// Retry returns the failed tool at the position in the tools file, if it still has the same name.
+func (s State) Retry(index int, name string) (Tool, bool) {
+	for _, failed := range s.Failed {
+		if failed.Index == index && failed.Name == name {
+			return failed, true
+		}
+	}
+
+	return Tool{}, false
+}
+
+// Missing returns the failed tools which are no longer found at the same position, given the names of the tools.
+func (s State) Missing(names []string) []Tool {
+	var missing []Tool
+
+	for _, failed := range s.Failed {
+		if failed.Index >= len(names) || names[failed.Index] != failed.Name {
+			missing = append(missing, failed)
+		}
+	}
+
+	return missing
+}
+
+// Resolve resolves the relative path against the working folder of the run.
+// Absolute paths, paths relative to the home folder and empty ones are returned as is.
+func (s State) Resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	return filepath.Join(s.Dir, path)
+}
+
+// Path returns the path of the state file.
+func Path() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "godyl", File), nil
+}
+
+// Load reads the state of the last run.
+func Load() (State, error) {
+	var state State
+
+	path, err := Path()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, ErrNoState
+		}
+
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	return state, nil
+}
+
+// Save writes the state, replacing the state of the previous run.
+// A tools file given by a relative path is stored as absolute, so that retrying works from any folder.
+// The working folder defaults to the current one.
+func (s State) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(s.Tools); err == nil && info.Mode().IsRegular() {
+		if s.Tools, err = filepath.Abs(s.Tools); err != nil {
+			return err
+		}
+	}
+
+	s.Time = time.Now()
+
+	if s.Dir == "" {
+		if s.Dir, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that concurrent runs never leave a partial state.
+	temp, err := os.CreateTemp(filepath.Dir(path), File+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+
+		return err
+	}
+
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package runstate_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/idelchi/godyl/internal/runstate"
+)
+
+// cache points the user cache folder to a temporary folder.
+func cache(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func TestSaveLoad(t *testing.T) {
+	cache(t)
+
+	if _, err := runstate.Load(); !errors.Is(err, runstate.ErrNoState) {
+		t.Fatalf("Load() error = %v, want %v", err, runstate.ErrNoState)
+	}
+
+	state := runstate.State{
+		// A relative tools file is stored as absolute, resolved against the working folder of the test.
+		Tools:  "runstate.go",
+		Tags:   []string{"cli", "!slow"},
+		Output: "bin",
+		Failed: []runstate.Tool{
+			{Index: 1, Name: "helm", Version: "v3.15.4", Error: "no match found"},
+			{Index: 3, Name: "kubectl", Error: "dependency failed"},
+		},
+	}
+
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := runstate.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got.Time.IsZero() {
+		t.Error("Time is zero, want the time of the run")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Dir != wd {
+		t.Errorf("Dir = %q, want %q", got.Dir, wd)
+	}
+
+	if want := filepath.Join(wd, "runstate.go"); got.Tools != want {
+		t.Errorf("Tools = %q, want %q", got.Tools, want)
+	}
+
+	if got.Output != state.Output || !slices.Equal(got.Tags, state.Tags) || !slices.Equal(got.Failed, state.Failed) {
+		t.Errorf("Load() = %+v, want %+v", got, state)
+	}
+
+	// Saving again replaces the state, keeping a working folder which is already set.
+	state = runstate.State{Tools: "idelchi/godyl", Dir: t.TempDir()}
+
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if got, err = runstate.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got.Tools != state.Tools || got.Dir != state.Dir || len(got.Failed) != 0 {
+		t.Errorf("Load() = %+v, want %+v", got, state)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	state := runstate.State{
+		Failed: []runstate.Tool{
+			{Index: 0, Name: "helm", Version: "v3.15.4"},
+			{Index: 2, Name: "kubectl"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		index int
+		tool  string
+		want  bool
+	}{
+		{name: "failed", index: 0, tool: "helm", want: true},
+		{name: "failed later", index: 2, tool: "kubectl", want: true},
+		{name: "succeeded", index: 1, tool: "task", want: false},
+		{name: "moved", index: 1, tool: "helm", want: false},
+		{name: "replaced", index: 2, tool: "task", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := state.Retry(tt.index, tt.tool)
+			if ok != tt.want {
+				t.Fatalf("Retry(%d, %q) = %v, want %v", tt.index, tt.tool, ok, tt.want)
+			}
+
+			if ok && (got.Index != tt.index || got.Name != tt.tool) {
+				t.Errorf("Retry(%d, %q) = %+v", tt.index, tt.tool, got)
+			}
+		})
+	}
+
+	if got, _ := state.Retry(0, "helm"); got.Version != "v3.15.4" {
+		t.Errorf("Retry() version = %q, want %q", got.Version, "v3.15.4")
+	}
+}
+
+func TestMissing(t *testing.T) {
+	t.Parallel()
+
+	state := runstate.State{
+		Failed: []runstate.Tool{
+			{Index: 0, Name: "helm"},
+			{Index: 2, Name: "kubectl"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "unchanged", names: []string{"helm", "task", "kubectl"}, want: nil},
+		{name: "renamed", names: []string{"helm", "task", "kubecolor"}, want: []string{"kubectl"}},
+		{name: "reordered", names: []string{"task", "helm", "kubectl"}, want: []string{"helm"}},
+		{name: "removed", names: []string{"helm"}, want: []string{"kubectl"}},
+		{name: "empty", names: nil, want: []string{"helm", "kubectl"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, tool := range state.Missing(tt.names) {
+				got = append(got, tool.Name)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Missing(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "project")
+	state := runstate.State{Dir: dir}
+
+	absolute := filepath.Join(t.TempDir(), "bin")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "relative", path: "bin", want: filepath.Join(dir, "bin")},
+		{name: "parent", path: filepath.Join("..", "bin"), want: filepath.Join(filepath.Dir(dir), "bin")},
+		{name: "absolute", path: absolute, want: absolute},
+		{name: "home", path: "~/.local/bin", want: "~/.local/bin"},
+		{name: "empty", path: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := state.Resolve(tt.path); got != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
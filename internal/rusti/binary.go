@@ -1,6 +1,7 @@
 package rusti
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -89,7 +90,7 @@ func (b *Binary) Download(target string) error {
 
 	downloader := download.New()
 
-	destination, err := downloader.Download(url, b.Dir.Path())
+	destination, err := downloader.Download(context.Background(), url, b.Dir.Path())
 	if err != nil {
 		return fmt.Errorf("downloading %q: %w", url, err)
 	}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/internal/manifest"
+	"github.com/idelchi/godyl/internal/tools/sources/command"
+	"github.com/idelchi/godyl/pkg/env"
+	"github.com/idelchi/godyl/pkg/file"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrShell is returned when completions are given for an unsupported shell.
+var ErrShell = errors.New("unsupported shell")
+
+// Shells returns the roles of the shells for which completions can be generated.
+func Shells() []Role {
+	return []Role{Bash, Zsh, Fish, Powershell}
+}
+
+// CompletionFile returns the name of the completion file for the executable,
+// following the conventions of the shell's completion loader.
+func (r Role) CompletionFile(exe string) string {
+	switch r {
+	case Zsh:
+		return "_" + exe
+	case Fish:
+		return exe + ".fish"
+	case Powershell:
+		return exe + ".ps1"
+	default:
+		return exe
+	}
+}
+
+// Completions maps a shell to the command printing the shell's completion script.
+type Completions map[Role]command.Command
+
+// UnmarshalYAML implements custom unmarshaling for Completions,
+// allowing a single command to be given for all shells, in which `{{ .Shell }}` is the name of the shell.
+func (c *Completions) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = make(Completions, len(Shells()))
+
+		for _, shell := range Shells() {
+			(*c)[shell] = command.Command(value.Value)
+		}
+
+		return nil
+	}
+
+	var completions map[Role]command.Command
+	if err := value.Decode(&completions); err != nil {
+		return err
+	}
+
+	*c = completions
+
+	return nil
+}
+
+// Validate checks that completions are only given for supported shells.
+func (c Completions) Validate() error {
+	for shell := range c {
+		if !slices.Contains(Shells(), shell) {
+			return fmt.Errorf("%w: completions for %q, allowed are %v", ErrShell, shell, Shells())
+		}
+	}
+
+	return nil
+}
+
+// GenerateCompletions runs the completion commands, with the output folder prepended to the PATH,
+// and writes their output into the folders of the shells. The written files are recorded in the manifest.
+// It returns the folders which did not exist before, per shell. The commands are stopped when the context is cancelled.
+// Nothing is generated if the executables cannot run on the host, as for cross-platform or emulated installs.
+func (t *Tool) GenerateCompletions(ctx context.Context) (map[Role]string, error) {
+	if len(t.Completions) == 0 || !t.Runnable() {
+		return nil, nil
+	}
+
+	exe := strings.TrimSuffix(t.Exe.Name, t.Platform.Extension.String())
+
+	record, err := manifest.Load(manifest.Path(t.Output, t.Exe.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	record.Tool = t.Name
+
+	environment := t.completionEnv()
+	created := make(map[Role]string)
+
+	for _, shell := range Shells() {
+		cmd, ok := t.Completions[shell]
+		if !ok {
+			continue
+		}
+
+		script, err := cmd.Shell(ctx, environment.ToSlice()...)
+		if err != nil {
+			return created, fmt.Errorf("generating %s completions: %w", shell, err)
+		}
+
+		dir, err := t.Directories.For(shell, t.Output, exe)
+		if err != nil {
+			return created, err
+		}
+
+		folder := file.NewFolder(dir)
+		if !folder.Exists() {
+			if err := folder.Create(); err != nil {
+				return created, fmt.Errorf("creating %s completions folder: %w", shell, err)
+			}
+
+			created[shell] = dir
+		}
+
+		target := filepath.Join(dir, shell.CompletionFile(exe))
+		if err := os.WriteFile(target, []byte(script), 0o644); err != nil {
+			return created, fmt.Errorf("writing %s completions: %w", shell, err)
+		}
+
+		record.Add(target)
+	}
+
+	return created, record.Save()
+}
+
+// Runnable checks whether the installed executables run natively on the host,
+// that is, they are not emulated and target the host's OS and architecture.
+func (t *Tool) Runnable() bool {
+	if t.Emulated {
+		return false
+	}
+
+	var (
+		hostOS   platform.OS
+		hostArch platform.Architecture
+	)
+
+	if hostOS.Parse(runtime.GOOS) != nil || hostArch.Parse(runtime.GOARCH) != nil {
+		return false
+	}
+
+	return t.Platform.OS.Type == hostOS.Type && t.Platform.Architecture.Type == hostArch.Type
+}
+
+// completionEnv returns the tool's environment with the output folder prepended to the PATH,
+// so that the installed executables can be called by name.
+func (t *Tool) completionEnv() env.Env {
+	environment := env.Env{}
+	environment.Merge(t.Env)
+
+	key := "PATH"
+
+	for k := range environment {
+		if strings.EqualFold(k, "PATH") {
+			key = k
+
+			break
+		}
+	}
+
+	output, err := filepath.Abs(t.Output)
+	if err != nil {
+		output = t.Output
+	}
+
+	environment[key] = output + string(os.PathListSeparator) + environment[key]
+
+	return environment
+}
@@ -17,6 +17,8 @@ type Defaults struct {
 	Output string
 	// Platform defines default platform-specific settings (e.g., OS and architecture).
 	Platform detect.Platform
+	// Emulation lists the architectures that can be run through emulation, per platform.
+	Emulation Emulation
 	// Values contains default custom values for the tool configuration.
 	Values map[string]any
 	// Fallbacks defines default fallback configurations or sources in case the primary configuration fails.
@@ -35,8 +37,16 @@ type Defaults struct {
 	Env env.Env
 	// Mode specifies the default operating mode for the tool (e.g., silent mode, verbose mode).
 	Mode Mode
+	// Versions configures default side-by-side installations of several versions of the tools.
+	Versions Versions
+	// Backups is the default number of previously installed executables kept when replacing them.
+	Backups int
+	// Timeout specifies the default time limits for the tool, in total and per phase.
+	Timeout Timeout
 	// Version specifies the default version details for the tool.
 	Version Version
+	// Directories defines the default folders into which the additional files are placed, per role.
+	Directories Directories
 }
 
 // Initialize detects the current platform and applies platform-specific defaults to the Defaults struct.
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCycle indicates that tools depend on each other in a cycle.
+var ErrCycle = errors.New("dependency cycle")
+
+// Dependencies returns, for each tool, the positions of the tools it depends on by name.
+// It fails if a tool depends on a name not in the collection, or if the dependencies form a cycle.
+func (t Tools) Dependencies() ([][]int, error) {
+	byName := make(map[string][]int)
+	for i, tool := range t {
+		byName[tool.Name] = append(byName[tool.Name], i)
+	}
+
+	dependencies := make([][]int, len(t))
+
+	for i, tool := range t {
+		for _, name := range tool.DependsOn {
+			indices, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("tool %q depends on %q, which is not in the tools", tool.Name, name)
+			}
+
+			dependencies[i] = append(dependencies[i], indices...)
+		}
+	}
+
+	if cycle := findCycle(dependencies); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, index := range cycle {
+			names[i] = t[index].Name
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(names, " -> "))
+	}
+
+	return dependencies, nil
+}
+
+// findCycle returns the positions forming a cycle in the dependencies, starting and ending with the same one,
+// or nil if there is none.
+func findCycle(dependencies [][]int) []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(dependencies))
+
+	var path []int
+
+	var visit func(int) []int
+
+	visit = func(i int) []int {
+		state[i] = visiting
+		path = append(path, i)
+
+		for _, dependency := range dependencies[i] {
+			switch state[dependency] {
+			case visiting:
+				for start, index := range path {
+					if index == dependency {
+						return append(path[start:], dependency)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[i] = visited
+
+		return nil
+	}
+
+	for i := range dependencies {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
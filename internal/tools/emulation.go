@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/unmarshal"
+)
+
+// Emulation maps a platform, given as "os/arch", to the architectures it can additionally run through emulation,
+// such as "darwin/arm64" running amd64 binaries through Rosetta 2.
+type Emulation map[string]unmarshal.SingleOrSlice[string]
+
+// For returns the architectures that can be run through emulation on the given platform.
+func (e Emulation) For(p detect.Platform) ([]platform.Architecture, error) {
+	key := fmt.Sprintf("%s/%s", p.OS.Type, p.Architecture.Type)
+
+	var architectures []platform.Architecture
+
+	for target, archs := range e {
+		if !strings.EqualFold(target, key) {
+			continue
+		}
+
+		for _, arch := range archs {
+			var architecture platform.Architecture
+			if err := architecture.Parse(arch); err != nil {
+				return nil, fmt.Errorf("parsing emulated architecture for %q: %w", target, err)
+			}
+
+			architectures = append(architectures, architecture)
+		}
+	}
+
+	return architectures, nil
+}
@@ -1,9 +1,14 @@
 package tools
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/fatih/structs"
 
 	"github.com/idelchi/godyl/pkg/unmarshal"
+	"github.com/idelchi/godyl/pkg/utils"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,10 +22,19 @@ type Exe struct {
 	// Patterns specifies the patterns used to locate the binary in the downloaded folder.
 	// This can either be a single string or a slice of strings.
 	Patterns unmarshal.SingleOrSlice[string]
+	// Aliases are additional names for the binary, used to create symlinks.
+	// For the primary executable, they are added to the tool's aliases.
+	Aliases Aliases
+	// Primary marks the executable used for version checks, when several executables are given.
+	// Defaults to the first one.
+	Primary bool
+	// Extra holds the additional executables installed from the same download, when several are given.
+	Extra []Exe `json:"-" mapstructure:"-" yaml:"-"`
 }
 
 // UnmarshalYAML implements custom unmarshaling for Exe,
-// allowing the YAML to either provide just the name as a scalar or the full Exe structure.
+// allowing the YAML to either provide just the name as a scalar, the full Exe structure,
+// or a list of executables, of which the primary one is stored in Exe and the others in Extra.
 func (e *Exe) UnmarshalYAML(value *yaml.Node) error {
 	// If the YAML value is a scalar (e.g., just the name), handle it directly by setting the Name field.
 	if value.Kind == yaml.ScalarNode {
@@ -29,8 +43,81 @@ func (e *Exe) UnmarshalYAML(value *yaml.Node) error {
 		return nil
 	}
 
+	if value.Kind == yaml.SequenceNode {
+		return e.unmarshalList(value)
+	}
+
 	// Perform custom unmarshaling with field validation, allowing only known fields.
 	type raw Exe
 
 	return unmarshal.DecodeWithOptionalKnownFields(value, (*raw)(e), true, structs.New(e).Name())
 }
+
+// unmarshalList unmarshals a list of executables, selecting the primary one.
+func (e *Exe) unmarshalList(value *yaml.Node) error {
+	var executables []Exe
+	if err := value.Decode(&executables); err != nil {
+		return err
+	}
+
+	if len(executables) == 0 {
+		return errors.New("exe: at least one executable must be given")
+	}
+
+	primary := 0
+
+	for i, executable := range executables {
+		if !executable.Primary {
+			continue
+		}
+
+		if executables[primary].Primary && primary != i {
+			return fmt.Errorf("exe: only one executable can be primary, got %q and %q",
+				executables[primary].Name, executable.Name)
+		}
+
+		primary = i
+	}
+
+	*e = executables[primary]
+
+	for i, executable := range executables {
+		if i == primary {
+			continue
+		}
+
+		// Only the primary executable's name can be inferred
+		if executable.Name == "" {
+			return fmt.Errorf("exe: additional executable #%d has no name", i+1)
+		}
+
+		e.Extra = append(e.Extra, executable)
+	}
+
+	return nil
+}
+
+// Complete appends the platform extension to the name and aliases of the executable,
+// and sets the pattern to find it by its exact name, if none are given.
+func (e *Exe) Complete(extension string) {
+	if !strings.HasSuffix(e.Name, extension) {
+		e.Name += extension
+	}
+
+	utils.SetSliceIfNil(&e.Patterns, fmt.Sprintf("^%s$", e.Name))
+
+	for i, alias := range e.Aliases {
+		e.Aliases[i] = alias + extension
+	}
+}
+
+// Names returns the names of the primary and all additional executables.
+func (e Exe) Names() []string {
+	names := []string{e.Name}
+
+	for _, extra := range e.Extra {
+		names = append(names, extra.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,7 @@
+package tools
+
+// Unexported functions, exposed to the tests.
+var (
+	OverrideMatches = overrideMatches
+	FindCycle       = findCycle
+)
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/tools/sources/common"
+	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/utils"
+)
+
+// ErrRole is returned when a file is mapped to an unknown role.
+var ErrRole = errors.New("unknown file role")
+
+// Role defines where an additional file of a download is placed.
+type Role string
+
+const (
+	// Man places man pages into the section subfolder of the man folder.
+	Man Role = "man"
+	// Bash places bash completions into the bash completions folder.
+	Bash Role = "bash"
+	// Zsh places zsh completions into the zsh completions folder.
+	Zsh Role = "zsh"
+	// Fish places fish completions into the fish completions folder.
+	Fish Role = "fish"
+	// Powershell places PowerShell completions into the PowerShell completions folder.
+	Powershell Role = "powershell"
+	// Share places files into the tool's subfolder of the share folder.
+	Share Role = "share"
+	// License places license files into the tool's subfolder of the license folder.
+	License Role = "license"
+)
+
+// Roles returns all available roles.
+func Roles() []Role {
+	return []Role{Man, Bash, Zsh, Fish, Powershell, Share, License}
+}
+
+// Files maps glob patterns, matched against the files of a download, to the role of the matching files.
+type Files map[string]Role
+
+// Validate checks that all files are mapped to a known role.
+func (f Files) Validate() error {
+	for pattern, role := range f {
+		if !slices.Contains(Roles(), role) {
+			return fmt.Errorf("%w: %q for %q, allowed are %v", ErrRole, role, pattern, Roles())
+		}
+	}
+
+	return nil
+}
+
+// Directories holds the folders into which the additional files are placed, per role.
+// Relative folders are relative to the output folder.
+type Directories struct {
+	// Man is the root of the man pages, containing the section folders.
+	Man string
+	// Bash is the folder for bash completions.
+	Bash string
+	// Zsh is the folder for zsh completions.
+	Zsh string
+	// Fish is the folder for fish completions.
+	Fish string
+	// Powershell is the folder for PowerShell completions.
+	Powershell string
+	// Share is the folder containing a subfolder with shared data per tool.
+	Share string
+	// License is the folder containing a subfolder with the licenses per tool.
+	License string
+}
+
+// ApplyDefaults sets the folders which are not set to the defaults.
+func (d *Directories) ApplyDefaults(defaults Directories) {
+	utils.SetIfEmpty(&d.Man, defaults.Man)
+	utils.SetIfEmpty(&d.Bash, defaults.Bash)
+	utils.SetIfEmpty(&d.Zsh, defaults.Zsh)
+	utils.SetIfEmpty(&d.Fish, defaults.Fish)
+	utils.SetIfEmpty(&d.Powershell, defaults.Powershell)
+	utils.SetIfEmpty(&d.Share, defaults.Share)
+	utils.SetIfEmpty(&d.License, defaults.License)
+}
+
+// Fields returns pointers to all folders, for templating.
+func (d *Directories) Fields() []*string {
+	return []*string{&d.Man, &d.Bash, &d.Zsh, &d.Fish, &d.Powershell, &d.Share, &d.License}
+}
+
+// For returns the absolute folder for files of the given role, resolved against the output folder.
+// Shared data and licenses are placed into a subfolder named after the tool.
+func (d Directories) For(role Role, output, tool string) (string, error) {
+	var dir string
+
+	switch role {
+	case Man:
+		dir = d.Man
+	case Bash:
+		dir = d.Bash
+	case Zsh:
+		dir = d.Zsh
+	case Fish:
+		dir = d.Fish
+	case Powershell:
+		dir = d.Powershell
+	case Share:
+		dir = filepath.Join(d.Share, tool)
+	case License:
+		dir = filepath.Join(d.License, tool)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrRole, role)
+	}
+
+	folder := file.NewFolder(dir)
+	if err := folder.Expand(); err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(folder.Path()) {
+		folder = file.NewFolder(output, folder.Path())
+	}
+
+	// The placed files are recorded by absolute path, to be removable regardless of the working directory
+	return filepath.Abs(folder.Path())
+}
+
+// FileRules returns the rules for placing the tool's additional files, sorted by pattern.
+func (t *Tool) FileRules() ([]common.FileRule, error) {
+	patterns := make([]string, 0, len(t.Files))
+	for pattern := range t.Files {
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Strings(patterns)
+
+	tool := strings.TrimSuffix(t.Exe.Name, t.Platform.Extension.String())
+
+	rules := make([]common.FileRule, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		role := t.Files[pattern]
+
+		folder, err := t.Directories.For(role, t.Output, tool)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, common.FileRule{Pattern: pattern, Folder: folder, Man: role == Man})
+	}
+
+	return rules, nil
+}
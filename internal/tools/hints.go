@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/pkg/file"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotSequence is returned when a tools file does not hold a list of tools.
+var ErrNotSequence = errors.New("tools file is not a list of tools")
+
+// SaveHints appends the hints to the tools at the given indices in the tools file.
+// Only the lines of the affected tools are rewritten, leaving the rest of the file byte for byte as it was,
+// and the file is replaced atomically.
+// Tools given by name only are expanded into their full form.
+func SaveHints(path string, hints map[int]match.Hint) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("parsing %q: %w", path, err)
+	}
+
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 || document.Content[0].Kind != yaml.SequenceNode {
+		return fmt.Errorf("%w: %q", ErrNotSequence, path)
+	}
+
+	entries := document.Content[0].Content
+	lines := strings.SplitAfter(string(data), "\n")
+
+	// Edit from the end, so that the lines of the preceding entries keep their positions.
+	for _, index := range slices.Backward(slices.Sorted(maps.Keys(hints))) {
+		if index < 0 || index >= len(entries) {
+			return fmt.Errorf("tool #%d not found in %q", index+1, path)
+		}
+
+		// The position is taken first, as expanding a tool given by name replaces its node.
+		start, next := entries[index].Line-1, len(lines)
+		if index+1 < len(entries) {
+			next = entries[index+1].Line - 1
+		}
+
+		if err := appendHint(entries[index], hints[index]); err != nil {
+			return fmt.Errorf("tool #%d in %q: %w", index+1, path, err)
+		}
+
+		if lines, err = replaceEntry(lines, entries[index], start, next); err != nil {
+			return fmt.Errorf("tool #%d in %q: %w", index+1, path, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var tx file.Transaction
+
+	if err := tx.Write(file.File(path), []byte(strings.Join(lines, "")), info.Mode().Perm()); err != nil {
+		tx.Discard()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceEntry replaces the lines of the entry of a block sequence with the entry encoded anew.
+// The entry spans from its first line up to the next entry, excluding the blank and comment lines preceding it.
+func replaceEntry(lines []string, entry *yaml.Node, start, next int) ([]string, error) {
+	indent := lines[start][:len(lines[start])-len(strings.TrimLeft(lines[start], " "))]
+	if !strings.HasPrefix(lines[start][len(indent):], "-") {
+		return nil, errors.New("only tools in block style can be edited")
+	}
+
+	end := next
+	for end > start+1 {
+		trimmed := strings.TrimSpace(lines[end-1])
+		if trimmed != "" && !(strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(lines[end-1], indent+" ")) {
+			break
+		}
+
+		end--
+	}
+
+	// Comments before and after the entry are kept in place, outside the replaced lines.
+	entry.HeadComment, entry.FootComment = "", ""
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{entry}}); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	encoded := strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i := range encoded {
+		encoded[i] = indent + encoded[i]
+	}
+
+	encoded[len(encoded)-1] += "\n"
+
+	return slices.Concat(lines[:start], encoded, lines[end:]), nil
+}
+
+// appendHint adds the hint to the tool node, creating or converting the `hints` field as needed.
+func appendHint(tool *yaml.Node, hint match.Hint) error {
+	// A tool given by name only is expanded to `name: <name>`
+	if tool.Kind == yaml.ScalarNode {
+		name := *tool
+
+		*tool = yaml.Node{
+			Kind:        yaml.MappingNode,
+			Tag:         "!!map",
+			HeadComment: name.HeadComment,
+			FootComment: name.FootComment,
+		}
+
+		// Line comments stay with the name
+		name.HeadComment, name.FootComment = "", ""
+
+		tool.Content = append(tool.Content, scalar("name"), &name)
+	}
+
+	if tool.Kind != yaml.MappingNode {
+		return errors.New("tool is neither a name nor a mapping")
+	}
+
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			scalar("pattern"), scalar(hint.Pattern),
+			scalar("must"), {Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+		},
+	}
+
+	for i := 0; i < len(tool.Content)-1; i += 2 {
+		if tool.Content[i].Value != "hints" {
+			continue
+		}
+
+		hints := tool.Content[i+1]
+
+		switch hints.Kind {
+		case yaml.SequenceNode:
+			hints.Content = append(hints.Content, node)
+		case yaml.MappingNode:
+			// A single hint is converted into a list of hints
+			tool.Content[i+1] = &yaml.Node{
+				Kind:    yaml.SequenceNode,
+				Tag:     "!!seq",
+				Content: []*yaml.Node{hints, node},
+			}
+		default:
+			tool.Content[i+1] = &yaml.Node{
+				Kind:    yaml.SequenceNode,
+				Tag:     "!!seq",
+				Content: []*yaml.Node{node},
+			}
+		}
+
+		return nil
+	}
+
+	tool.Content = append(tool.Content, scalar("hints"), &yaml.Node{
+		Kind:    yaml.SequenceNode,
+		Tag:     "!!seq",
+		Content: []*yaml.Node{node},
+	})
+
+	return nil
+}
+
+// scalar returns a string node with the given value.
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/structs"
+
+	"github.com/idelchi/godyl/pkg/unmarshal"
+	"github.com/idelchi/godyl/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrChecksumMismatch indicates that an included tools file does not match its pinned checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// client fetches remote tools files.
+var client = &http.Client{Timeout: time.Minute}
+
+// Include references other tools files whose tools are loaded in place of the `include` entry.
+type Include struct {
+	// Path is a local path or glob, relative to the including file, or an HTTPS URL.
+	Path string
+	// SHA256 optionally pins the content of the file.
+	SHA256 string `mapstructure:"sha256" yaml:"sha256"`
+}
+
+// UnmarshalYAML implements custom unmarshaling for Include,
+// allowing the YAML to either provide just the path, or the full Include structure.
+func (i *Include) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&i.Path)
+	}
+
+	type raw Include
+
+	return unmarshal.DecodeWithOptionalKnownFields(value, (*raw)(i), true, structs.New(i).Name())
+}
+
+// Origin locates the definition of a tool, as the tools file and the position of the entry within it.
+type Origin struct {
+	// File is the path or URL of the tools file, or `-` for stdin.
+	File string
+	// Index is the position of the entry in the file.
+	Index int
+}
+
+// Remote checks whether the tool was defined in a remote tools file.
+func (o Origin) Remote() bool {
+	return utils.IsURL(o.File)
+}
+
+// loader loads tools files, following their includes.
+type loader struct {
+	// loaded holds the files already loaded, so that each file is included once, and cycles end.
+	loaded map[string]bool
+}
+
+// parse loads the tools from the content of the file, replacing `include` entries with the tools they reference.
+// Tools defined in the file itself replace included tools of the same name.
+func (l *loader) parse(file string, data []byte) (Tools, error) {
+	var entries []yaml.Node
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	var tools Tools
+
+	defined := make(map[string]bool)
+
+	for index, entry := range entries {
+		if include, ok, err := includeOf(&entry); err != nil {
+			return nil, err
+		} else if ok {
+			included, err := l.include(file, include)
+			if err != nil {
+				return nil, fmt.Errorf("including %q: %w", include.Path, err)
+			}
+
+			tools = append(tools, included...)
+
+			continue
+		}
+
+		var tool Tool
+		if err := entry.Decode(&tool); err != nil {
+			return nil, err
+		}
+
+		tool.Origin = Origin{File: file, Index: index}
+		defined[tool.Name] = true
+
+		tools = append(tools, tool)
+	}
+
+	return slices.DeleteFunc(tools, func(tool Tool) bool {
+		return tool.Origin.File != file && defined[tool.Name]
+	}), nil
+}
+
+// includeOf returns the include of an entry consisting of only an `include` key.
+func includeOf(entry *yaml.Node) (include Include, ok bool, err error) {
+	if entry.Kind != yaml.MappingNode || len(entry.Content) != 2 || entry.Content[0].Value != "include" {
+		return include, false, nil
+	}
+
+	if err := entry.Content[1].Decode(&include); err != nil {
+		return include, false, err
+	}
+
+	if include.Path == "" {
+		return include, false, fmt.Errorf("line %d: include without a path", entry.Line)
+	}
+
+	return include, true, nil
+}
+
+// include loads the tools of the files referenced by the include, resolving relative paths against the file.
+func (l *loader) include(file string, include Include) (Tools, error) {
+	if utils.IsURL(include.Path) || utils.IsURL(file) {
+		location := include.Path
+
+		if utils.IsURL(file) {
+			base, err := url.Parse(file)
+			if err != nil {
+				return nil, err
+			}
+
+			reference, err := url.Parse(include.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			location = base.ResolveReference(reference).String()
+		}
+
+		if l.loaded[location] {
+			return nil, nil
+		}
+
+		l.loaded[location] = true
+
+		data, err := fetch(location, include.SHA256)
+		if err != nil {
+			return nil, err
+		}
+
+		return l.parse(location, data)
+	}
+
+	pattern := include.Path
+	if !filepath.IsAbs(pattern) && file != "-" {
+		pattern = filepath.Join(filepath.Dir(file), pattern)
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	glob := strings.ContainsAny(include.Path, "*?[")
+
+	switch {
+	case glob && include.SHA256 != "":
+		return nil, errors.New("a glob cannot be pinned by a checksum")
+	case !glob:
+		// Missing files are reported when reading them.
+		paths = []string{pattern}
+	}
+
+	var tools Tools
+
+	for _, path := range paths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if l.loaded[absolute] {
+			continue
+		}
+
+		l.loaded[absolute] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verify(path, data, include.SHA256); err != nil {
+			return nil, err
+		}
+
+		included, err := l.parse(path, data)
+		if err != nil {
+			return nil, fmt.Errorf("loading %q: %w", path, err)
+		}
+
+		tools = append(tools, included...)
+	}
+
+	return tools, nil
+}
+
+// verify checks the content against the checksum, if given.
+func verify(location string, data []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256(data)
+
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, checksum) {
+		return fmt.Errorf("%w: %q: expected sha256 %s, got %s", ErrChecksumMismatch, location, checksum, actual)
+	}
+
+	return nil
+}
+
+// fetch downloads the remote tools file, keeping a copy in the user cache folder.
+// A cached copy matching the checksum is used without downloading, and
+// the cached copy is used as well when the file cannot be downloaded, e.g. when offline.
+func fetch(location, checksum string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("%q: only HTTPS URLs are supported", location)
+	}
+
+	cache := cachePath(location)
+
+	cached, cacheErr := os.ReadFile(cache)
+	if cacheErr == nil && checksum != "" && verify(location, cached, checksum) == nil {
+		return cached, nil
+	}
+
+	data, err := download(location)
+	if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		data = cached
+	}
+
+	if err := verify(location, data, checksum); err != nil {
+		return nil, err
+	}
+
+	// Caching is best effort.
+	if cache != "" && (cacheErr != nil || !bytes.Equal(cached, data)) {
+		if err := os.MkdirAll(filepath.Dir(cache), 0o755); err == nil {
+			_ = os.WriteFile(cache, data, 0o644)
+		}
+	}
+
+	return data, nil
+}
+
+// download fetches the content of the URL.
+func download(location string) ([]byte, error) {
+	response, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %q: %s", location, response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+// cachePath returns the path caching the remote tools file, or an empty string if there is no cache folder.
+func cachePath(location string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(location))
+
+	return filepath.Join(dir, "godyl", "includes", hex.EncodeToString(sum[:])+".yml")
+}
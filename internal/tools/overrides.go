@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/pkg/utils"
+)
+
+// ErrOverride is returned when an override is not keyed by a valid platform.
+var ErrOverride = errors.New("invalid override")
+
+// Overrides maps a platform, given as "os", "os/arch" or "os/arch/library", to a partial tool configuration
+// which is merged over the tool's configuration on matching platforms.
+// Only non-zero values are merged, so an override cannot reset a field to its zero value, such as `false` or "".
+type Overrides map[string]Tool
+
+// For returns the overrides matching the given platform, from the least to the most specific.
+func (o Overrides) For(p detect.Platform) ([]Tool, error) {
+	keys := make([]string, 0, len(o))
+
+	for key := range o {
+		matches, err := overrideMatches(key, p)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if depth, other := strings.Count(keys[i], "/"), strings.Count(keys[j], "/"); depth != other {
+			return depth < other
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	tools := make([]Tool, 0, len(keys))
+
+	for _, key := range keys {
+		tools = append(tools, o[key])
+	}
+
+	return tools, nil
+}
+
+// Validate checks that all overrides are keyed by a valid platform.
+func (o Overrides) Validate() error {
+	var errs []error
+
+	for key := range o {
+		if _, err := overrideMatches(key, detect.Platform{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// overrideMatches checks whether the platform given as key matches the platform.
+// Architectures with a version, such as "armv7" or "x86-64-v3", only match the same version.
+func overrideMatches(key string, p detect.Platform) (bool, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) > 3 {
+		return false, fmt.Errorf("%w: %q: expected \"os\", \"os/arch\" or \"os/arch/library\"", ErrOverride, key)
+	}
+
+	var os platform.OS
+	if err := os.Parse(parts[0]); err != nil {
+		return false, fmt.Errorf("%w: %q: %w", ErrOverride, key, err)
+	}
+
+	matches := os.Type == p.OS.Type
+
+	if len(parts) > 1 {
+		var arch platform.Architecture
+		if err := arch.Parse(parts[1]); err != nil {
+			return false, fmt.Errorf("%w: %q: %w", ErrOverride, key, err)
+		}
+
+		// A bare "arm" parses as armv5, but is meant to match any ARM version.
+		explicit := arch.Version != 0 && arch.Raw != "arm"
+
+		matches = matches && arch.Type == p.Architecture.Type &&
+			(!explicit || arch.Version == p.Architecture.Version)
+	}
+
+	if len(parts) > 2 {
+		var library platform.Library
+		if err := library.Parse(parts[2]); err != nil {
+			return false, fmt.Errorf("%w: %q: %w", ErrOverride, key, err)
+		}
+
+		matches = matches && library.Type == p.Library.Type
+	}
+
+	return matches, nil
+}
+
+// ApplyOverrides merges the overrides matching the tool's platform over the tool's configuration.
+// Only values set in an override are applied; slices are replaced while maps and nested fields are merged.
+// The overrides are validated first, so that an invalid one fails the tool instead of being skipped.
+func (t *Tool) ApplyOverrides() error {
+	if err := t.Overrides.Validate(); err != nil {
+		return err
+	}
+
+	overrides, err := t.Overrides.For(t.Platform)
+	if err != nil {
+		return err
+	}
+
+	for _, override := range overrides {
+		// Overrides cannot be nested
+		override.Overrides = nil
+
+		utils.MergeNonZero(t, override)
+	}
+
+	return nil
+}
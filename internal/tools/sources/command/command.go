@@ -25,7 +25,8 @@ func (c *Command) From(command string) {
 
 // Shell runs the Command using mvdan/sh, capturing both stdout and stderr output.
 // It accepts optional environment variables and returns the stdout output and any errors encountered.
-func (c Command) Shell(env ...string) (string, error) {
+// The command is stopped when the context is cancelled.
+func (c Command) Shell(ctx context.Context, env ...string) (string, error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 
 	// Parse the command string into a shell script
@@ -45,7 +46,7 @@ func (c Command) Shell(env ...string) (string, error) {
 	}
 
 	// Execute the parsed command
-	err = runner.Run(context.TODO(), file)
+	err = runner.Run(ctx, file)
 	if err != nil {
 		return "", fmt.Errorf(
 			"running shell command: %w: stdout: %s: stderr: %s",
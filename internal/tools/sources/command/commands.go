@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -28,12 +29,12 @@ func (*Commands) Exe() error {
 }
 
 // Version sets the version for the commands. (Ineffective).
-func (*Commands) Version(_ string) error {
+func (*Commands) Version(_ context.Context, _ string) error {
 	return nil
 }
 
 // Path sets up the path for the commands, using the provided parameters. (Ineffective).
-func (*Commands) Path(_ string, _ []string, _ string, _ match.Requirements) error {
+func (*Commands) Path(_ context.Context, _ string, _ []string, _ string, _ match.Requirements) error {
 	return nil
 }
 
@@ -49,11 +50,11 @@ func (c Commands) Combined() Command {
 
 // Install runs the combined commands for installation using the provided InstallData,
 // captures the output, and returns it alongside any errors or found file information.
-func (c Commands) Install(d common.InstallData) (output string, found file.File, err error) {
+func (c Commands) Install(ctx context.Context, d common.InstallData) (output string, found file.File, err error) {
 	cmd := c.Combined()
 
 	// Execute the combined command
-	output, err = cmd.Shell(d.Env.ToSlice()...)
+	output, err = cmd.Shell(ctx, d.Env.ToSlice()...)
 	if err != nil {
 		return output, "", fmt.Errorf("running combined commands: %w", err)
 	}
@@ -1,10 +1,19 @@
 package common
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
 	"regexp"
+	"time"
 
+	"github.com/idelchi/godyl/internal/backup"
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/detect/platform"
+	"github.com/idelchi/godyl/internal/inspect"
+	"github.com/idelchi/godyl/internal/match"
 	"github.com/idelchi/godyl/pkg/download"
 	"github.com/idelchi/godyl/pkg/env"
 	"github.com/idelchi/godyl/pkg/file"
@@ -13,126 +22,367 @@ import (
 // InstallData holds the details required for downloading and installing files,
 // including the path, executable name, output directory, and environment settings.
 type InstallData struct {
-	Path        string   // The URL or path to download from
-	Name        string   // The name of the file or project
-	Exe         string   // The name of the executable
-	Patterns    []string // Patterns to match files for the executable
-	Output      string   // Output directory for the installation
-	Aliases     []string // Aliases for the executable
-	Mode        string   // Mode of operation, such as "find" for locating executables
-	Env         env.Env  // Environment variables for the installation process
-	NoVerifySSL bool     // Skip SSL verification
+	Path            string                  // The URL or path to download from
+	Name            string                  // The name of the file or project
+	Exe             string                  // The name of the executable
+	Patterns        []string                // Patterns to match files for the executable
+	Output          string                  // Output directory for the installation
+	ExeOutput       string                  // Output directory for the executables, if other than Output
+	Aliases         []string                // Aliases for the executable
+	Mode            string                  // Mode of operation, such as "find" for locating executables
+	Env             env.Env                 // Environment variables for the installation process
+	NoVerifySSL     bool                    // Skip SSL verification
+	LockTimeout     time.Duration           // Time to wait for other processes holding locks on shared folders
+	DownloadTimeout time.Duration           // Time limit for downloading, without limit if not positive
+	Platform        detect.Platform         // Platform the executable must target
+	Emulated        []platform.Architecture // Architectures the platform can run through emulation
+	Hints           match.Hints             // Hints to match against the downloaded files
+	Extra           []Executable            // Additional executables to install from the same download
+	Files           []FileRule              // Additional files to install from the same download
+	Extract         ExtractOptions          // Options for extracting the download in `extract` mode
+	Backups         int                     // Number of backups to keep of replaced executables, disabled if not positive
+}
+
+// exeOutput returns the output directory for the executables.
+func (d InstallData) exeOutput() string {
+	if d.ExeOutput != "" {
+		return d.ExeOutput
+	}
+
+	return d.Output
+}
+
+// backup keeps copies of the executables about to be replaced, unless disabled.
+// Versions installed side by side are not backed up, as they are kept anyway.
+func (d InstallData) backup(executables ...Executable) error {
+	if d.Backups <= 0 || d.ExeOutput != "" {
+		return nil
+	}
+
+	kept := make([]backup.Executable, len(executables))
+	for i, executable := range executables {
+		kept[i] = backup.Executable{Name: executable.Name, Aliases: executable.Aliases}
+	}
+
+	if err := backup.Create(d.Output, d.Name, kept, d.Backups); err != nil {
+		return fmt.Errorf("backing up previous installation: %w", err)
+	}
+
+	return nil
+}
+
+// Executable describes an executable to install from a download.
+type Executable struct {
+	Name     string   // The name under which the executable is stored
+	Patterns []string // Patterns to match files for the executable
+	Aliases  []string // Aliases for the executable
 }
 
 // Download handles downloading files based on the InstallData configuration.
 // It creates a temporary folder if needed and manages the download process.
-func Download(d InstallData) (string, file.File, error) {
+// The download is aborted when the context is cancelled.
+func Download(ctx context.Context, d InstallData) (string, file.File, error) {
 	var err error
 	var found file.File
 
-	folder := file.Folder(d.Output)
+	// Downloads are always extracted into a temporary folder first.
+	var folder file.Folder
 
-	if d.Mode == "find" {
-		if err := folder.CreateRandomInTempDir(); err != nil {
-			return "", "", fmt.Errorf("creating temp dir: %w", err)
-		}
-		defer func() {
-			if err == nil {
-				folder.Remove()
-			}
-		}()
+	if err := folder.CreateRandomInTempDir(); err != nil {
+		return "", "", fmt.Errorf("creating temp dir: %w", err)
 	}
+	defer func() {
+		if err == nil || IsMismatch(err) || ctx.Err() != nil {
+			folder.Remove()
+		}
+	}()
 
 	downloader := download.New()
 	downloader.InsecureSkipVerify = d.NoVerifySSL
 
-	destination, err := downloader.Download(d.Path, folder.Path())
+	if d.DownloadTimeout > 0 {
+		downloader.ContextTimeout = d.DownloadTimeout
+	}
+
+	var destination file.File
+
+	err = WithTimeout(ctx, "download", d.DownloadTimeout, func(ctx context.Context) (err error) {
+		destination, err = downloader.Download(ctx, d.Path, folder.Path())
+
+		return err
+	})
 	if err != nil {
 		return "", "", fmt.Errorf("downloading %q: %w", d.Path, err)
 	}
 
 	if d.Mode == "find" {
+		if err = MatchContent(destination, d.Hints); err != nil {
+			return "", "", err
+		}
+
 		found, err = FindAndSymlink(destination, d)
+	} else {
+		err = Extract(destination, d)
 	}
 
 	return "", found, err
 }
 
+// IsMismatch checks whether the error indicates that the downloaded asset is not suitable,
+// in which case another asset may be tried instead.
+func IsMismatch(err error) bool {
+	return errors.Is(err, inspect.ErrMismatch) || errors.Is(err, match.ErrContent)
+}
+
+// MatchContent checks the files of the downloaded destination against the content hints.
+// The paths are relative to the destination, using forward slashes.
+func MatchContent(destination file.File, hints match.Hints) error {
+	if len(hints.Content()) == 0 {
+		return nil
+	}
+
+	var files []string
+
+	if destination.IsDir() {
+		root := destination.String()
+
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			relative, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			files = append(files, filepath.ToSlash(relative))
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("listing files in %q: %w", destination, err)
+		}
+	} else {
+		files = append(files, filepath.Base(destination.String()))
+	}
+
+	if err := hints.MatchContent(files); err != nil {
+		return fmt.Errorf("checking contents of %q: %w", destination, err)
+	}
+
+	return nil
+}
+
 // FindAndSymlink finds the executable within the downloaded folder and creates symlinks for it
 // based on the provided InstallData. It handles directories and sets up aliases as needed.
+// Additional executables are installed from the same download, and the primary executable is returned.
 func FindAndSymlink(destination file.File, d InstallData) (file.File, error) {
-	if destination.IsDir() {
-		searchDir := destination.Dir()
+	primary := Executable{Name: d.Exe, Patterns: d.Patterns, Aliases: d.Aliases}
 
-		folders, err := searchDir.ListFolders()
-		if err != nil {
-			return destination, fmt.Errorf("listing folders in %q: %w", searchDir, err)
+	if !destination.IsDir() {
+		if len(d.Extra) > 0 {
+			return destination, fmt.Errorf(
+				"finding executables: %q is a single file and cannot provide additional executables",
+				destination,
+			)
 		}
 
-		files, err := searchDir.ListFiles()
-		if err != nil {
-			return destination, fmt.Errorf("listing files in %q: %w", searchDir, err)
+		if _, err := SelectExecutable(file.Files{destination}, d.Platform, d.Emulated...); err != nil {
+			return destination, err
 		}
 
-		if len(folders) == 1 && len(files) == 0 {
-			searchDir = folders[0]
+		if err := d.backup(primary); err != nil {
+			return destination, err
 		}
 
-		var found bool
-		// Match patterns in order of priority
-		for _, pattern := range d.Patterns {
-			match := func(file file.File) (bool, error) {
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					return false, fmt.Errorf("compiling pattern %q: %w", pattern, err)
-				}
-				matched := re.MatchString(file.Normalized().Name())
+		if err := install(d.exeOutput(), []Executable{primary}, file.Files{destination}); err != nil {
+			return destination, err
+		}
 
-				if matched {
-					return true, nil
-				}
+		return destination, InstallFiles("", d)
+	}
 
-				return false, nil
-			}
+	searchDir := destination.Dir()
 
-			var err error
+	folders, err := searchDir.ListFolders()
+	if err != nil {
+		return destination, fmt.Errorf("listing folders in %q: %w", searchDir, err)
+	}
 
-			destination, err = searchDir.FindFile(match)
-			if err != nil {
-				if !errors.Is(err, file.ErrNotFound) {
-					return destination, err
-				}
-				continue
-			} else {
-				found = true
+	files, err := searchDir.ListFiles()
+	if err != nil {
+		return destination, fmt.Errorf("listing files in %q: %w", searchDir, err)
+	}
 
-				break
+	if len(folders) == 1 && len(files) == 0 {
+		searchDir = folders[0]
+	}
+
+	// Find all executables before installing any of them, as a mismatch means another asset will be tried.
+	executables := append([]Executable{primary}, d.Extra...)
+	found := make(file.Files, len(executables))
+
+	for i, executable := range executables {
+		found[i], err = executable.Find(searchDir, d.Platform, d.Emulated...)
+		if err != nil {
+			return destination, err
+		}
+	}
+
+	if err := d.backup(executables...); err != nil {
+		return found[0], err
+	}
+
+	if err := install(d.exeOutput(), executables, found); err != nil {
+		return found[0], err
+	}
+
+	return found[0], InstallFiles(searchDir, d)
+}
+
+// Find searches the folder for the executable, trying the patterns in order of priority.
+// It returns an error wrapping inspect.ErrMismatch if only binaries for another platform were found.
+func (e Executable) Find(
+	searchDir file.Folder,
+	target detect.Platform,
+	emulated ...platform.Architecture,
+) (file.File, error) {
+	var mismatches []error
+
+	for _, pattern := range e.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("compiling pattern %q: %w", pattern, err)
+		}
+
+		match := func(file file.File) (bool, error) {
+			return re.MatchString(file.Normalized().Name()), nil
+		}
+
+		candidates, err := searchDir.FindFiles(match)
+		if err != nil {
+			if !errors.Is(err, file.ErrNotFound) {
+				return "", err
 			}
+
+			continue
 		}
 
-		if !found {
-			return destination, fmt.Errorf(
-				"finding executable: no executable matching patterns %v found in %q",
-				d.Patterns,
-				searchDir,
-			)
+		candidate, err := SelectExecutable(candidates, target, emulated...)
+		if err != nil {
+			mismatches = append(mismatches, err)
+
+			continue
 		}
+
+		return candidate, nil
+	}
+
+	if len(mismatches) > 0 {
+		return "", fmt.Errorf("finding executable %q in %q: %w", e.Name, searchDir, errors.Join(mismatches...))
 	}
 
-	folder := file.NewFolder(d.Output)
+	return "", fmt.Errorf(
+		"finding executable: no executable %q matching patterns %v found in %q",
+		e.Name,
+		e.Patterns,
+		searchDir,
+	)
+}
+
+// install places the found files as the executables in the output directory, all or none of them.
+func install(output string, executables []Executable, found file.Files) error {
+	var tx file.Transaction
+	defer tx.Discard()
+
+	for i, executable := range executables {
+		if err := executable.Stage(&tx, found[i], output); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("installing executables: %w", err)
+	}
+
+	return nil
+}
+
+// Stage stages the found file as the executable in the output directory, along with symlinks for its aliases.
+// Nothing is replaced until the transaction is committed.
+func (e Executable) Stage(tx *file.Transaction, found file.File, output string) error {
+	folder := file.NewFolder(output)
 	if !folder.Exists() {
 		if err := folder.Create(); err != nil {
-			return destination, fmt.Errorf("creating output folder: %w", err)
+			return fmt.Errorf("creating output folder: %w", err)
 		}
 	}
 
-	// Copy the executable to the output directory
-	target := file.NewFile(d.Output, d.Exe)
-	if err := destination.Copy(target); err != nil {
-		return destination, fmt.Errorf("copying %q to %q: %w", destination, target, err)
+	target := file.NewFile(output, e.Name)
+	if err := tx.Copy(found, target, 0o755); err != nil {
+		return err
+	}
+
+	return tx.Symlink(target, file.NewFiles(output, e.Aliases...)...)
+}
+
+// SelectExecutable picks the executable among the candidates by inspecting their headers.
+// Binaries that target the given platform are preferred, followed by files which are not binaries
+// (such as scripts), and lastly binaries that are not executables (such as shared libraries).
+// Binaries for one of the emulated architectures are accepted as well.
+// It returns an error wrapping inspect.ErrMismatch if all candidates are binaries for another platform,
+// and errors reading the candidates as they are.
+func SelectExecutable(
+	candidates file.Files,
+	target detect.Platform,
+	emulated ...platform.Architecture,
+) (file.File, error) {
+	var script, library file.File
+	var mismatches []error
+
+	for _, candidate := range candidates {
+		binary, err := inspect.Inspect(candidate)
+		if err != nil {
+			if errors.Is(err, inspect.ErrNotBinary) {
+				if script == "" {
+					script = candidate
+				}
+
+				continue
+			}
+
+			// Only binaries which cannot be parsed count as mismatches, failing to read them does not.
+			if !errors.Is(err, inspect.ErrUnsupported) {
+				return "", err
+			}
+
+			mismatches = append(mismatches, fmt.Errorf("%w: %q: %w", inspect.ErrMismatch, candidate, err))
+
+			continue
+		}
+
+		if err := binary.Supports(target, emulated...); err != nil {
+			mismatches = append(mismatches, fmt.Errorf("%q: %w", candidate, err))
+
+			continue
+		}
+
+		if binary.Executable {
+			return candidate, nil
+		}
+
+		if library == "" {
+			library = candidate
+		}
 	}
 
-	// Create symlinks for the aliases
-	aliases := file.NewFiles(d.Output, d.Aliases...)
-	return destination, aliases.SymlinksFor(target)
+	switch {
+	case script != "":
+		return script, nil
+	case library != "":
+		return library, nil
+	default:
+		return "", errors.Join(mismatches...)
+	}
 }
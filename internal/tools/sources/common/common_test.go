@@ -1 +1,153 @@
 package common_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idelchi/godyl/internal/tools/sources/common"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// write creates the files, relative to the folder, with their content.
+func write(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// read returns the files below the folder, relative to it, with their content.
+func read(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		relative, _ := filepath.Rel(dir, path)
+		files[filepath.ToSlash(relative)] = string(data)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestExtractClean(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output")
+	target := filepath.Join(output, "tool")
+
+	data := common.InstallData{
+		Name:    "tool",
+		Exe:     "tool",
+		Output:  output,
+		Extract: common.ExtractOptions{Target: "tool", Clean: true},
+	}
+
+	write(t, output, map[string]string{"shared": "other tool"})
+	write(t, target, map[string]string{"bin/tool": "old", "lib/stale/file": "stale"})
+
+	first := filepath.Join(dir, "first")
+	write(t, first, map[string]string{"bin/tool": "new", "doc/README": "readme"})
+
+	if err := common.Extract(file.File(first), data); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := map[string]string{"bin/tool": "new", "doc/README": "readme"}
+	if got := read(t, target); !maps.Equal(got, want) {
+		t.Errorf("target = %v, want %v", got, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "lib")); !os.IsNotExist(err) {
+		t.Errorf("empty folder left behind, stat error = %v", err)
+	}
+
+	if got := read(t, output)["shared"]; got != "other tool" {
+		t.Errorf("shared file = %q, want it untouched", got)
+	}
+
+	// A file in the way of a folder fails staging, which must leave the previous files in place.
+	write(t, target, map[string]string{"conflict": "file"})
+
+	second := filepath.Join(dir, "second")
+	write(t, second, map[string]string{"bin/tool": "newer", "conflict/file": "folder"})
+
+	if err := common.Extract(file.File(second), data); err == nil {
+		t.Fatal("Extract() error = nil, want an error")
+	}
+
+	want["conflict"] = "file"
+	if got := read(t, target); !maps.Equal(got, want) {
+		t.Errorf("target after failure = %v, want %v", got, want)
+	}
+}
+
+func TestInstallFilesKeepsPreviousOnFailure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output")
+	man := filepath.Join(output, "man")
+
+	data := common.InstallData{
+		Name:   "tool",
+		Exe:    "tool",
+		Output: output,
+		Files:  []common.FileRule{{Pattern: "*.1", Folder: man}},
+	}
+
+	first := filepath.Join(dir, "first")
+	write(t, first, map[string]string{"doc/tool.1": "first"})
+
+	if err := common.InstallFiles(file.Folder(first), data); err != nil {
+		t.Fatalf("InstallFiles() error = %v", err)
+	}
+
+	manifest := read(t, filepath.Join(output, ".godyl"))["tool.json"]
+
+	// A file in the way of a folder fails staging, which must leave the previous files and manifest in place.
+	write(t, output, map[string]string{"blocked": "file"})
+
+	data.Files = append(data.Files, common.FileRule{Pattern: "*.md", Folder: filepath.Join(output, "blocked", "docs")})
+
+	second := filepath.Join(dir, "second")
+	write(t, second, map[string]string{"doc/other.1": "second", "README.md": "readme"})
+
+	if err := common.InstallFiles(file.Folder(second), data); err == nil {
+		t.Fatal("InstallFiles() error = nil, want an error")
+	}
+
+	if got, want := read(t, man), map[string]string{"tool.1": "first"}; !maps.Equal(got, want) {
+		t.Errorf("files after failure = %v, want %v", got, want)
+	}
+
+	if got := read(t, filepath.Join(output, ".godyl"))["tool.json"]; got != manifest {
+		t.Errorf("manifest after failure = %s, want %s", got, manifest)
+	}
+}
@@ -0,0 +1,300 @@
+package common
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/manifest"
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/pkg/file"
+	"github.com/idelchi/godyl/pkg/unmarshal"
+)
+
+// ErrUnsafeClean is returned when cleaning could delete unrelated files.
+var ErrUnsafeClean = errors.New("refusing to clean")
+
+// ExtractOptions defines how the downloaded files are extracted into the output folder in `extract` mode.
+type ExtractOptions struct {
+	// StripComponents removes the given number of leading path components, skipping files with fewer components.
+	StripComponents int `mapstructure:"strip-components" yaml:"strip-components"`
+	// Include lists glob patterns of the files to extract. All files are extracted if empty.
+	Include unmarshal.SingleOrSlice[string]
+	// Exclude lists glob patterns of the files not to extract.
+	Exclude unmarshal.SingleOrSlice[string]
+	// Target is the folder within the output folder to extract into, dedicated to the tool.
+	Target string
+	// Clean empties the target folder before extracting.
+	Clean bool
+}
+
+// Validate checks the patterns, the number of components to strip and the target folder.
+// Cleaning requires a target folder, as the output folder may be shared with other tools.
+func (o ExtractOptions) Validate() error {
+	if o.StripComponents < 0 {
+		return fmt.Errorf("extract: strip-components must not be negative, got %d", o.StripComponents)
+	}
+
+	if o.Target != "" && (!filepath.IsLocal(o.Target) || filepath.Clean(o.Target) == ".") {
+		return fmt.Errorf("extract: target must be a folder within the output folder, got %q", o.Target)
+	}
+
+	if o.Clean && o.Target == "" {
+		return fmt.Errorf("%w: clean requires a target folder dedicated to the tool", ErrUnsafeClean)
+	}
+
+	for _, pattern := range slices.Concat(o.Include, o.Exclude) {
+		if err := (match.Hint{Pattern: pattern, Type: match.Glob}).Validate(); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Selects checks whether the file, given by its path after stripping, is to be extracted.
+// A pattern matching any of the parent folders applies to all files within.
+func (o ExtractOptions) Selects(name string) bool {
+	return (len(o.Include) == 0 || matchesAny(o.Include, name)) && !matchesAny(o.Exclude, name)
+}
+
+// matchesAny checks whether any of the glob patterns match the path or one of its parent folders.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		hint := match.Hint{Pattern: pattern, Type: match.Glob}
+
+		for prefix := name; prefix != "." && prefix != "/"; prefix = path.Dir(prefix) {
+			if hint.Matches(prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Strip removes the leading path components from the slash-separated path.
+// It returns false if the path has no components left.
+func (o ExtractOptions) Strip(name string) (string, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) <= o.StripComponents {
+		return "", false
+	}
+
+	return strings.Join(parts[o.StripComponents:], "/"), true
+}
+
+// Extract copies the downloaded destination into the output folder, or its target folder, applying the extract options.
+// The extracted files are moved into place together, and the files of the previous extraction no longer extracted,
+// as recorded in the manifest, are removed along with them, as are all other files of the target folder if cleaning.
+// The extracted files are recorded in the manifest, written in the same step.
+// If any step fails, the previous files and manifest are restored.
+func Extract(destination file.File, d InstallData) error {
+	output, err := filepath.Abs(d.Output)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(output, d.Extract.Target)
+
+	record, err := manifest.Load(manifest.Path(output, d.Exe))
+	if err != nil {
+		return err
+	}
+
+	// The files to clean are listed before staging, so that the staged files are not among them.
+	var cleaned []string
+
+	if d.Extract.Clean {
+		if cleaned, err = cleanable(target); err != nil {
+			return err
+		}
+	}
+
+	// entries holds the files to extract, by their source and target paths.
+	var entries []extractEntry
+
+	extract := func(source, relative string, entry fs.DirEntry) error {
+		name, ok := d.Extract.Strip(filepath.ToSlash(relative))
+		if !ok || !d.Extract.Selects(name) {
+			return nil
+		}
+
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("extracting %q: path escapes the output folder", name)
+		}
+
+		entries = append(entries, extractEntry{source: source, target: filepath.Join(target, filepath.FromSlash(name)), entry: entry})
+
+		return nil
+	}
+
+	if destination.IsDir() {
+		root := destination.String()
+
+		err = filepath.WalkDir(root, func(source string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			relative, err := filepath.Rel(root, source)
+			if err != nil {
+				return err
+			}
+
+			return extract(source, relative, entry)
+		})
+	} else {
+		// A single downloaded file is extracted by its name
+		var info fs.FileInfo
+
+		if info, err = os.Lstat(destination.String()); err == nil {
+			err = extract(destination.String(), filepath.Base(destination.String()), fs.FileInfoToDirEntry(info))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Symlinks are staged last, as on Windows they are copies of the files they point to.
+	slices.SortStableFunc(entries, func(a, b extractEntry) int {
+		return cmp.Compare(a.entry.Type()&fs.ModeSymlink, b.entry.Type()&fs.ModeSymlink)
+	})
+
+	var tx file.Transaction
+	defer tx.Discard()
+
+	extracted := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		if err := e.stage(&tx); err != nil {
+			return fmt.Errorf("extracting %q: %w", e.target, err)
+		}
+
+		extracted = append(extracted, e.target)
+	}
+
+	stale := record.Stale(extracted)
+
+	for _, path := range slices.Concat(stale, cleaned) {
+		tx.Remove(file.File(path))
+	}
+
+	record.Tool = d.Name
+	record.Files = nil
+	record.Add(extracted...)
+
+	// The manifest is replaced along with the files, so that it always matches them.
+	if err := record.Stage(&tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("extracting: %w", err)
+	}
+
+	record.Prune(stale...)
+
+	if d.Extract.Clean {
+		removeEmptyFolders(target)
+	}
+
+	return nil
+}
+
+// cleanable lists the files of the target folder, to be removed when cleaning it.
+// It refuses to clean the root, home and working folders (or their parents).
+func cleanable(target string) ([]string, error) {
+	protected := []string{string(filepath.Separator)}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		protected = append(protected, home)
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		protected = append(protected, wd)
+	}
+
+	for _, dir := range protected {
+		relative, err := filepath.Rel(target, dir)
+		if err == nil && (relative == "." || filepath.IsLocal(relative)) {
+			return nil, fmt.Errorf("%w: %q contains %q", ErrUnsafeClean, target, dir)
+		}
+	}
+
+	var files []string
+
+	err := filepath.WalkDir(target, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		files = append(files, path)
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("listing target folder: %w", err)
+	}
+
+	return files, nil
+}
+
+// removeEmptyFolders removes the folders within the target folder left empty, deepest first.
+func removeEmptyFolders(target string) {
+	var folders []string
+
+	filepath.WalkDir(target, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() && path != target {
+			folders = append(folders, path)
+		}
+
+		return nil
+	})
+
+	// Removing a folder fails if it is not empty.
+	for _, folder := range slices.Backward(folders) {
+		os.Remove(folder)
+	}
+}
+
+// extractEntry is a file to extract, from its source to its target path.
+type extractEntry struct {
+	source string
+	target string
+	entry  fs.DirEntry
+}
+
+// stage stages a copy of a regular file, preserving its permissions, or of a symlink.
+func (e extractEntry) stage(tx *file.Transaction) error {
+	if err := os.MkdirAll(filepath.Dir(e.target), 0o755); err != nil {
+		return err
+	}
+
+	if e.entry.Type()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(e.source)
+		if err != nil {
+			return err
+		}
+
+		return tx.Link(link, file.File(e.target))
+	}
+
+	info, err := e.entry.Info()
+	if err != nil {
+		return err
+	}
+
+	return tx.Copy(file.File(e.source), file.File(e.target), info.Mode().Perm())
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/idelchi/godyl/internal/manifest"
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// FileRule places the files of a download matching a glob pattern into a folder.
+type FileRule struct {
+	Pattern string // Glob pattern, matched against the relative path or, if it contains no slash, the file name
+	Folder  string // Folder to place the matching files into
+	Man     bool   // Place the files into the subfolder of their man section, such as "man1"
+}
+
+// Target returns the path the file is placed at.
+func (r FileRule) Target(f file.File) string {
+	name := filepath.Base(f.String())
+
+	if !r.Man {
+		return filepath.Join(r.Folder, name)
+	}
+
+	return filepath.Join(r.Folder, "man"+manSection(name), name)
+}
+
+// manSection returns the man section of a page, derived from its extension (e.g. "rg.1" or "rg.1.gz").
+// Pages without a section are placed into section 1.
+func manSection(name string) string {
+	extension := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(name, ".gz")), ".")
+
+	if extension == "" || extension[0] < '1' || extension[0] > '9' {
+		return "1"
+	}
+
+	return extension[:1]
+}
+
+// InstallFiles places the files of the download matching the rules, replacing the files placed by
+// a previous installation as recorded in the manifest. A root of "" only removes the previous files.
+// The new files and the manifest are moved into place, and the previous files removed, together,
+// only once all new files have been staged.
+func InstallFiles(root file.Folder, d InstallData) error {
+	record, err := manifest.Load(manifest.Path(d.Output, d.Exe))
+	if err != nil {
+		return err
+	}
+
+	var tx file.Transaction
+	defer tx.Discard()
+
+	var placed []string
+
+	if root != "" {
+		if placed, err = stageFiles(&tx, root, d.Files); err != nil {
+			return err
+		}
+	}
+
+	stale := record.Stale(placed)
+
+	for _, path := range stale {
+		tx.Remove(file.File(path))
+	}
+
+	record.Tool = d.Name
+	record.Files = nil
+	record.Add(placed...)
+
+	// The manifest is replaced along with the files, so that it always matches them.
+	if err := record.Stage(&tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("placing files: %w", err)
+	}
+
+	record.Prune(stale...)
+
+	return nil
+}
+
+// stageFiles stages copies of the files matching the rules into their folders, returning the staged paths.
+func stageFiles(tx *file.Transaction, root file.Folder, rules []FileRule) ([]string, error) {
+	var staged []string
+
+	for _, rule := range rules {
+		hint := match.Hint{Pattern: rule.Pattern, Type: match.Glob}
+
+		matches := func(f file.File) (bool, error) {
+			return hint.Matches(f.Normalized().String()), nil
+		}
+
+		files, err := root.FindFiles(matches)
+		if err != nil {
+			if errors.Is(err, file.ErrNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, f := range files {
+			target := file.File(rule.Target(f))
+
+			if err := target.Dir().Create(); err != nil {
+				return nil, fmt.Errorf("creating folder for %q: %w", target, err)
+			}
+
+			if err := tx.Copy(f, target, 0o644); err != nil {
+				return nil, err
+			}
+
+			staged = append(staged, target.String())
+		}
+	}
+
+	return staged, nil
+}
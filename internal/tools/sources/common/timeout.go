@@ -0,0 +1,29 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned when a phase of the installation does not complete in time.
+var ErrTimeout = errors.New("timed out")
+
+// WithTimeout runs the phase with a context expiring after the limit, without limit if not positive.
+// Errors caused by the expired limit are wrapped with ErrTimeout, naming the phase.
+func WithTimeout(ctx context.Context, phase string, limit time.Duration, run func(context.Context) error) error {
+	if limit <= 0 {
+		return run(ctx)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, limit, fmt.Errorf("%w: %s after %s", ErrTimeout, phase, limit))
+	defer cancel()
+
+	err := run(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), ErrTimeout) {
+		return fmt.Errorf("%w: %v", context.Cause(ctx), err)
+	}
+
+	return err
+}
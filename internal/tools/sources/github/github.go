@@ -1,7 +1,9 @@
 package github
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/idelchi/godyl/internal/github"
 	"github.com/idelchi/godyl/internal/match"
@@ -19,6 +21,17 @@ type GitHub struct {
 	Data common.Metadata `yaml:"-"`
 
 	latestStoredRelease *github.Release
+	// alternatives holds the URLs of the remaining qualified assets, from best to worst.
+	alternatives []string
+	// emulated holds the URLs of the assets which can only be run through emulation.
+	emulated map[string]bool
+	// explanation holds the evaluation of the release assets from the last match.
+	explanation match.Explanation
+}
+
+// Explanation returns how the release assets were evaluated during the last match.
+func (g *GitHub) Explanation() match.Explanation {
+	return g.explanation
 }
 
 // Get retrieves a specific attribute from the GitHub repository's metadata.
@@ -38,11 +51,11 @@ func (g *GitHub) Export() error {
 }
 
 // LatestVersion fetches the latest release version of the GitHub repository.
-func (g *GitHub) LatestVersion() (string, error) {
+func (g *GitHub) LatestVersion(ctx context.Context) (string, error) {
 	client := github.NewClient(g.Token)
 	repository := github.NewRepository(g.Owner, g.Repo, client)
 
-	release, err := repository.LatestRelease()
+	release, err := repository.LatestRelease(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -58,11 +71,11 @@ func (g *GitHub) LatestVersion() (string, error) {
 }
 
 // LatestVersion fetches the latest release version of the GitHub repository.
-func (g *GitHub) LatestVersionFromExport() (string, error) {
+func (g *GitHub) LatestVersionFromExport(ctx context.Context) (string, error) {
 	client := github.NewClient(g.Token)
 	repository := github.NewRepository(g.Owner, g.Repo, client)
 
-	release, err := repository.LatestReleaseFromExport()
+	release, err := repository.LatestReleaseFromExport(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -80,6 +93,7 @@ func (g *GitHub) LatestVersionFromExport() (string, error) {
 // MatchAssetsToRequirements matches release assets to specific file extensions and requirements,
 // returning the URL of the matched asset.
 func (g *GitHub) MatchAssetsToRequirements(
+	ctx context.Context,
 	filters []string,
 	version string,
 	requirements match.Requirements,
@@ -91,7 +105,7 @@ func (g *GitHub) MatchAssetsToRequirements(
 	if g.latestStoredRelease == nil {
 		var err error
 
-		release, err = repository.GetRelease(version)
+		release, err = repository.GetRelease(ctx, version)
 		if err != nil {
 			return "", err
 		}
@@ -101,7 +115,29 @@ func (g *GitHub) MatchAssetsToRequirements(
 
 	assets := release.Assets
 
+	g.explanation = match.Explanation{
+		Requirements: requirements,
+		Evaluations:  assets.Evaluate(requirements),
+	}
+
 	matches := assets.Match(requirements)
+
+	// Resolve ties between equally good assets, if possible.
+	if matches.HasQualified() && matches.IsAmbigious() {
+		switch {
+		case len(match.Hints(requirements.Hints).Content()) > 0:
+			// The assets are tried in turn after download, against the content hints.
+			matches = matches[:1]
+		case requirements.Chooser != nil:
+			choice, err := requirements.Chooser(matches)
+			if err != nil {
+				return "", err
+			}
+
+			matches = match.Results{choice}
+		}
+	}
+
 	if matches.Status() != nil {
 		return "", matches.WithoutZero().Status()
 	}
@@ -110,7 +146,23 @@ func (g *GitHub) MatchAssetsToRequirements(
 		return "", fmt.Errorf("no assets found for requirements: %v", requirements)
 	}
 
-	return assets.FilterByName(matches[0].Asset.Name)[0].URL, matches.Status()
+	url := assets.FilterByName(matches[0].Asset.Name)[0].URL
+
+	g.explanation.Winner = matches[0].Asset.Name
+
+	g.alternatives = nil
+	g.emulated = map[string]bool{url: matches[0].Emulated}
+
+	for _, candidate := range assets.Candidates(requirements) {
+		if candidate.Asset.Name != matches[0].Asset.Name {
+			alternative := assets.FilterByName(candidate.Asset.Name)[0].URL
+
+			g.alternatives = append(g.alternatives, alternative)
+			g.emulated[alternative] = candidate.Emulated
+		}
+	}
+
+	return url, matches.Status()
 }
 
 // PopulateOwnerAndRepo sets the Owner and Repo fields based on the given name.
@@ -152,8 +204,8 @@ func (g *GitHub) Exe() error {
 }
 
 // Version fetches and sets the latest release version in the metadata.
-func (g *GitHub) Version(name string) error {
-	version, err := g.LatestVersion()
+func (g *GitHub) Version(ctx context.Context, name string) error {
+	version, err := g.LatestVersion(ctx)
 	if err != nil {
 		return err
 	}
@@ -163,8 +215,14 @@ func (g *GitHub) Version(name string) error {
 }
 
 // Path sets the download URL of the matched asset in the metadata, based on version, file extensions, and requirements.
-func (g *GitHub) Path(_ string, extensions []string, version string, requirements match.Requirements) error {
-	url, err := g.MatchAssetsToRequirements(extensions, version, requirements)
+func (g *GitHub) Path(
+	ctx context.Context,
+	_ string,
+	extensions []string,
+	version string,
+	requirements match.Requirements,
+) error {
+	url, err := g.MatchAssetsToRequirements(ctx, extensions, version, requirements)
 	if err != nil {
 		return err
 	}
@@ -175,6 +233,21 @@ func (g *GitHub) Path(_ string, extensions []string, version string, requirement
 }
 
 // Install downloads the asset from GitHub and returns the output, the found file, and any error encountered.
-func (g *GitHub) Install(d common.InstallData) (output string, found file.File, err error) {
-	return common.Download(d)
+// If the executable in the asset does not target the requested platform,
+// or the asset's contents do not satisfy the content hints, the next-best asset is tried.
+// The path of the asset that was finally used is stored in the metadata.
+func (g *GitHub) Install(ctx context.Context, d common.InstallData) (output string, found file.File, err error) {
+	for _, path := range append([]string{d.Path}, g.alternatives...) {
+		d.Path = path
+
+		output, found, err = common.Download(ctx, d)
+		if !common.IsMismatch(err) {
+			break
+		}
+	}
+
+	g.Data.Set("path", d.Path)
+	g.Data.Set("emulated", strconv.FormatBool(g.emulated[d.Path]))
+
+	return output, found, err
 }
@@ -5,6 +5,7 @@
 package goc
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -45,12 +46,12 @@ func (g *Go) Exe() error {
 }
 
 // Version fetches and sets the version for the Go project.
-func (g *Go) Version(name string) error {
-	return g.github.Version(name)
+func (g *Go) Version(ctx context.Context, name string) error {
+	return g.github.Version(ctx, name)
 }
 
 // Path sets the path for the Go project based on its version, using the format github.com/{owner}/{repo}@{version}.
-func (g *Go) Path(_ string, _ []string, version string, _ match.Requirements) error {
+func (g *Go) Path(_ context.Context, _ string, _ []string, version string, _ match.Requirements) error {
 	g.github.Data.Set("path", fmt.Sprintf("github.com/%s/%s@%s", g.github.Owner, g.github.Repo, version))
 	return nil
 }
@@ -59,20 +60,33 @@ var mu sync.Mutex
 
 // Install installs the Go project by downloading and setting up the required files,
 // and returns the output, the found file, and any error encountered during installation.
-func (g *Go) Install(d common.InstallData) (output string, found file.File, err error) {
+func (g *Go) Install(ctx context.Context, d common.InstallData) (output string, found file.File, err error) {
+	var binary goi.Binary
+
 	mu.Lock()
-	binary, err := goi.New(d.NoVerifySSL)
+	err = common.WithTimeout(ctx, "download", d.DownloadTimeout, func(ctx context.Context) (err error) {
+		binary, err = goi.New(ctx, d.NoVerifySSL, d.LockTimeout)
+
+		return err
+	})
+	mu.Unlock()
+
 	if err != nil {
 		return "", "", err
 	}
-	mu.Unlock()
+
+	// The shared Go folder stays locked while installing, as `go install` uses it.
+	defer binary.Release()
 
 	installer := goi.Installer{
 		Binary: binary,
 	}
 
 	var folder file.Folder
-	folder.CreateRandomInTempDir()
+	if err := folder.CreateRandomInTempDir(); err != nil {
+		return "", "", fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer folder.Remove()
 
 	installer.Binary.Env.Append(
 		goi.Env{
@@ -99,7 +113,7 @@ func (g *Go) Install(d common.InstallData) (output string, found file.File, err
 	}
 
 	for _, path := range paths {
-		output, err = installer.Install(path)
+		output, err = installer.Install(ctx, path)
 
 		if err == nil {
 			d.Path = path
@@ -5,6 +5,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/idelchi/godyl/internal/match"
@@ -48,13 +49,13 @@ type Source struct {
 }
 
 // Populater defines the interface that all source types must implement to handle initialization, execution,
-// versioning, path setup, and installation.
+// versioning, path setup, and installation. The context cancels any network requests and commands.
 type Populater interface {
 	Initialize(string) error
 	Exe() error
-	Version(string) error
-	Path(string, []string, string, match.Requirements) error
-	Install(common.InstallData) (string, file.File, error)
+	Version(context.Context, string) error
+	Path(context.Context, string, []string, string, match.Requirements) error
+	Install(context.Context, common.InstallData) (string, file.File, error)
 	Get(string) string
 }
 
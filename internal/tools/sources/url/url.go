@@ -5,6 +5,7 @@
 package url
 
 import (
+	"context"
 	"github.com/idelchi/godyl/internal/match"
 	"github.com/idelchi/godyl/internal/tools/sources/common"
 	"github.com/idelchi/godyl/pkg/file"
@@ -35,18 +36,18 @@ func (u *URL) Exe() error {
 }
 
 // Version sets the version for the URL. (Ineffective).
-func (u *URL) Version(name string) error {
+func (u *URL) Version(_ context.Context, name string) error {
 	return nil
 }
 
 // Path sets the path for the URL, storing the provided name in the metadata.
-func (u *URL) Path(name string, _ []string, _ string, _ match.Requirements) error {
+func (u *URL) Path(_ context.Context, name string, _ []string, _ string, _ match.Requirements) error {
 	u.Data.Set("path", name)
 	return nil
 }
 
 // Install downloads the file from the URL and processes it based on the provided InstallData.
 // It returns the output, the downloaded file, and any error encountered.
-func (u *URL) Install(d common.InstallData) (output string, found file.File, err error) {
-	return common.Download(d)
+func (u *URL) Install(ctx context.Context, d common.InstallData) (output string, found file.File, err error) {
+	return common.Download(ctx, d)
 }
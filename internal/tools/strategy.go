@@ -1,10 +1,13 @@
 package tools
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"unicode"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/idelchi/godyl/internal/tools/sources/common"
 	"github.com/idelchi/godyl/internal/version"
 )
 
@@ -33,7 +36,7 @@ func (s Strategy) Check(t *Tool) error {
 
 // Upgrade checks if the tool should be upgraded based on the strategy and its current version.
 // It compares the existing version with the desired version and returns an error if the tool is already up to date.
-func (s Strategy) Upgrade(t *Tool) error {
+func (s Strategy) Upgrade(ctx context.Context, t *Tool) error {
 	// If the tool does not exist, no upgrade is necessary.
 	if !t.Exists() {
 		return nil
@@ -50,26 +53,22 @@ func (s Strategy) Upgrade(t *Tool) error {
 		}
 
 		// Parse the version of the existing tool.
-		exe := version.NewExecutable(t.Output, t.Exe.Name)
-
-		parser := &version.Version{
-			Patterns: t.Version.Patterns,
-			Commands: t.Version.Commands,
-		}
-
-		if err := exe.ParseVersion(parser); err != nil {
+		if err := t.queryPrevious(ctx); errors.Is(err, ErrTimeout) {
+			// A hanging executable is reported rather than silently replaced.
+			return err
+		} else if err != nil {
 			// Force an upgrade if the version cannot be parsed.
 			return nil
 		}
 
-		source := ToVersion(exe.Version)
+		source := ToVersion(t.Previous)
 		if source == nil {
-			return fmt.Errorf("parsing version %q: failed: %q -> %q", exe.Version, exe.Version, t.Version.Version)
+			return fmt.Errorf("parsing version %q: failed: %q -> %q", t.Previous, t.Previous, t.Version.Version)
 		}
 
 		target := ToVersion(t.Version.Version)
 		if target == nil {
-			return fmt.Errorf("parsing version %q: failed: %q -> %q", t.Version.Version, exe.Version, t.Version.Version)
+			return fmt.Errorf("parsing version %q: failed: %q -> %q", t.Version.Version, t.Previous, t.Version.Version)
 		}
 
 		// If the versions match, return an error indicating the tool is already up to date.
@@ -80,12 +79,46 @@ func (s Strategy) Upgrade(t *Tool) error {
 		return nil
 	case Force:
 		// If the strategy is "Force", always proceed with the installation or update.
+		// The version of the existing tool is only queried to report whether it changed.
+		if t.Version.Commands == nil || len(t.Version.Commands) > 0 {
+			_ = t.queryPrevious(ctx)
+		}
+
 		return nil
 	default:
 		return nil
 	}
 }
 
+// queryPrevious parses the version of the existing executable into Previous.
+func (t *Tool) queryPrevious(ctx context.Context) error {
+	exe := version.NewExecutable(t.Output, t.Exe.Name)
+
+	parser := &version.Version{
+		Patterns: t.Version.Patterns,
+		Commands: t.Version.Commands,
+	}
+
+	if err := common.WithTimeout(ctx, "version-check", t.Timeout.VersionCheck, func(ctx context.Context) error {
+		return exe.ParseVersion(ctx, parser)
+	}); err != nil {
+		return err
+	}
+
+	t.Previous = exe.Version
+
+	return nil
+}
+
+// SameVersion checks whether the versions are equal, comparing them as semantic versions if both parse as such.
+func SameVersion(a, b string) bool {
+	if x, y := ToVersion(a), ToVersion(b); x != nil && y != nil {
+		return x.Equal(y)
+	}
+
+	return a == b
+}
+
 // ToVersion attempts to convert the version string to a semantic version.
 func ToVersion(version string) *semver.Version {
 	for index := range len(version) {
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrTagExpression indicates that a tag expression cannot be parsed.
+var ErrTagExpression = errors.New("invalid tag expression")
+
+// TagExpression is a boolean expression over the tags of a tool,
+// combining tags with `&&`, `||`, `!` and parentheses.
+type TagExpression interface {
+	// Matches evaluates the expression against the tags.
+	Matches(tags Tags) bool
+	// String returns the expression in its canonical form.
+	String() string
+}
+
+type (
+	// tagExpression matches tools having the tag.
+	tagExpression string
+	// notExpression negates the expression.
+	notExpression struct{ operand TagExpression }
+	// andExpression matches if all of the expressions match.
+	andExpression []TagExpression
+	// orExpression matches if any of the expressions match.
+	orExpression []TagExpression
+)
+
+func (e tagExpression) Matches(tags Tags) bool { return slices.Contains(tags, string(e)) }
+
+func (e tagExpression) String() string { return string(e) }
+
+func (e notExpression) Matches(tags Tags) bool { return !e.operand.Matches(tags) }
+
+func (e notExpression) String() string {
+	switch e.operand.(type) {
+	case tagExpression, notExpression:
+		return "!" + e.operand.String()
+	}
+
+	return "!(" + e.operand.String() + ")"
+}
+
+func (e andExpression) Matches(tags Tags) bool {
+	return !slices.ContainsFunc(e, func(operand TagExpression) bool { return !operand.Matches(tags) })
+}
+
+func (e andExpression) String() string { return join(e, " && ") }
+
+func (e orExpression) Matches(tags Tags) bool {
+	return slices.ContainsFunc(e, func(operand TagExpression) bool { return operand.Matches(tags) })
+}
+
+func (e orExpression) String() string { return join(e, " || ") }
+
+// join joins the operands with the operator, parenthesizing nested operations.
+func join(operands []TagExpression, operator string) string {
+	parts := make([]string, len(operands))
+
+	for i, operand := range operands {
+		switch operand.(type) {
+		case andExpression, orExpression:
+			parts[i] = "(" + operand.String() + ")"
+		default:
+			parts[i] = operand.String()
+		}
+	}
+
+	return strings.Join(parts, operator)
+}
+
+// ParseTagExpression parses an expression such as `kubernetes && !(heavy || slow)`.
+// `!` binds tighter than `&&`, which binds tighter than `||`.
+func ParseTagExpression(expression string) (TagExpression, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrTagExpression, expression, err)
+	}
+
+	p := parser{tokens: tokens}
+
+	parsed, err := p.or()
+	if err == nil && p.position < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.position])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrTagExpression, expression, err)
+	}
+
+	return parsed, nil
+}
+
+// tokenize splits the expression into operators, parentheses and tags.
+func tokenize(expression string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(' || c == ')' || c == '!':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(expression) || expression[i+1] != c {
+				return nil, fmt.Errorf("expected %q at position %d", strings.Repeat(string(c), 2), i+1)
+			}
+
+			tokens = append(tokens, expression[i:i+2])
+			i += 2
+		default:
+			end := i
+			for end < len(expression) && !strings.ContainsRune(" \t\n()!&|", rune(expression[end])) {
+				end++
+			}
+
+			tokens = append(tokens, expression[i:end])
+			i = end
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	return tokens, nil
+}
+
+// parser is a recursive descent parser over the tokens of a tag expression.
+type parser struct {
+	tokens   []string
+	position int
+}
+
+// peek returns the current token, or an empty string at the end.
+func (p *parser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+
+	return ""
+}
+
+// or parses operands separated by `||`.
+func (p *parser) or() (TagExpression, error) {
+	return p.operation("||", p.and, func(operands []TagExpression) TagExpression { return orExpression(operands) })
+}
+
+// and parses operands separated by `&&`.
+func (p *parser) and() (TagExpression, error) {
+	return p.operation("&&", p.unary, func(operands []TagExpression) TagExpression { return andExpression(operands) })
+}
+
+// operation parses operands separated by the operator, combining several of them.
+func (p *parser) operation(
+	operator string,
+	operand func() (TagExpression, error),
+	combine func([]TagExpression) TagExpression,
+) (TagExpression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []TagExpression{first}
+
+	for p.peek() == operator {
+		p.position++
+
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, next)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	return combine(operands), nil
+}
+
+// unary parses a negation, a parenthesized expression or a tag.
+func (p *parser) unary() (TagExpression, error) {
+	switch token := p.peek(); token {
+	case "":
+		return nil, errors.New("unexpected end of expression")
+	case "!":
+		p.position++
+
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpression{operand: operand}, nil
+	case "(":
+		p.position++
+
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, errors.New("missing closing parenthesis")
+		}
+
+		p.position++
+
+		return inner, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("unexpected %q", token)
+	default:
+		p.position++
+
+		return tagExpression(token), nil
+	}
+}
+
+// TagFilter selects tools by their tags.
+// Tools must match all of the exclusions (expressions starting with `!`), and any of the inclusions, if given.
+type TagFilter struct {
+	Include []TagExpression
+	Exclude []TagExpression
+}
+
+// ParseTagFilter parses each of the expressions into the filter, ignoring empty ones.
+// Plain tags keep their meaning, e.g. `a`, `b` and `!c` select tools tagged with `a` or `b`, but not `c`.
+func ParseTagFilter(expressions []string) (TagFilter, error) {
+	var filter TagFilter
+
+	for _, expression := range expressions {
+		if strings.TrimSpace(expression) == "" {
+			continue
+		}
+
+		parsed, err := ParseTagExpression(expression)
+		if err != nil {
+			return filter, err
+		}
+
+		if _, ok := parsed.(notExpression); ok {
+			filter.Exclude = append(filter.Exclude, parsed)
+		} else {
+			filter.Include = append(filter.Include, parsed)
+		}
+	}
+
+	return filter, nil
+}
+
+// Includes checks whether the tags match any of the inclusions, or there are none.
+func (f TagFilter) Includes(tags Tags) bool {
+	return len(f.Include) == 0 || orExpression(f.Include).Matches(tags)
+}
+
+// Excludes checks whether the tags fail any of the exclusions.
+func (f TagFilter) Excludes(tags Tags) bool {
+	return !andExpression(f.Exclude).Matches(tags)
+}
@@ -1,7 +1,12 @@
 package tools
 
 import (
+	"path/filepath"
+
 	"github.com/idelchi/godyl/internal/templates"
+	"github.com/idelchi/godyl/internal/tools/sources/command"
+	"github.com/idelchi/godyl/pkg/env"
+	"github.com/idelchi/godyl/pkg/file"
 	"github.com/idelchi/godyl/pkg/utils"
 )
 
@@ -53,6 +58,12 @@ func (t *Tool) TemplateFirst() error {
 		return err
 	}
 
+	for _, dir := range t.Directories.Fields() {
+		if err := templates.ApplyAndSet(dir, values); err != nil {
+			return err
+		}
+	}
+
 	if err := templates.ApplyAndSet(&t.Version.Version, values); err != nil {
 		return err
 	}
@@ -60,6 +71,34 @@ func (t *Tool) TemplateFirst() error {
 	return nil
 }
 
+// OutputFolder returns the output folder of the tool, expanded and templated as when resolving the tool.
+// The tool is left untouched, so that the folder can be known before installing.
+func (t *Tool) OutputFolder() (string, error) {
+	output := file.Folder(t.Output)
+	if err := output.Expand(); err != nil {
+		return "", err
+	}
+
+	environment := t.Env.Merged(env.FromEnv())
+	environment.Expand()
+
+	values := t.ToTemplateMap(t.Platform.ToMap(), map[string]any{
+		"Env":    environment,
+		"Values": utils.NormalizeMap(t.Values),
+	})
+
+	templated, err := templates.Apply(output.Path(), values)
+	if err != nil {
+		return "", err
+	}
+
+	if t.Dir != "" && !filepath.IsAbs(templated) {
+		templated = filepath.Join(t.Dir, templated)
+	}
+
+	return templated, nil
+}
+
 func (t *Tool) TemplateLast() error {
 	values := t.ToTemplateMap(t.Platform.ToMap())
 
@@ -74,6 +113,23 @@ func (t *Tool) TemplateLast() error {
 		}
 	}
 
+	// Apply templating to the additional executables, with `.Exe` referring to the executable itself
+	for i := range t.Exe.Extra {
+		extra := &t.Exe.Extra[i]
+
+		if err := templates.ApplyAndSet(&extra.Name, values); err != nil {
+			return err
+		}
+
+		extraValues := t.ToTemplateMap(t.Platform.ToMap(), map[string]any{"Exe": extra.Name})
+
+		for j := range extra.Patterns {
+			if err := templates.ApplyAndSet(&extra.Patterns[j], extraValues); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Apply templating to Extensions
 	for i := range t.Extensions {
 		if err := templates.ApplyAndSet(&t.Extensions[i], values); err != nil {
@@ -113,6 +169,22 @@ func (t *Tool) TemplateLast() error {
 		if err := t.Hints[i].SetWeight(); err != nil {
 			return err
 		}
+
+		if err := t.Hints[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Apply templating to the completion commands, with `.Shell` referring to the shell
+	for shell, cmd := range t.Completions {
+		shellValues := t.ToTemplateMap(t.Platform.ToMap(), map[string]any{"Shell": string(shell)})
+
+		output, err := templates.Apply(cmd.String(), shellValues)
+		if err != nil {
+			return err
+		}
+
+		t.Completions[shell] = command.Command(output)
 	}
 
 	if err := templates.ApplyAndSet(&t.Path, values); err != nil {
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/structs"
+
+	"github.com/idelchi/godyl/pkg/unmarshal"
+	"github.com/idelchi/godyl/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Timeout limits the time spent on a tool, in total and per phase. Zero values mean no limit.
+type Timeout struct {
+	// Total limits the time for all phases together.
+	Total time.Duration
+	// Resolve limits fetching the version and the matching asset from the source.
+	Resolve time.Duration
+	// Download limits downloading the asset.
+	Download time.Duration
+	// Install limits the installation, including the download and e.g. `go install`.
+	Install time.Duration
+	// Post limits running the post-installation commands, and generating the completions.
+	Post time.Duration
+	// VersionCheck limits querying the version of the installed executable.
+	VersionCheck time.Duration `mapstructure:"version-check" yaml:"version-check"`
+}
+
+// UnmarshalYAML implements custom unmarshaling for Timeout,
+// allowing the YAML to either provide just the total time, or the full Timeout structure.
+func (t *Timeout) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&t.Total)
+	}
+
+	type raw Timeout
+
+	return unmarshal.DecodeWithOptionalKnownFields(value, (*raw)(t), true, structs.New(t).Name())
+}
+
+// ApplyDefaults sets the limits which are not set to the defaults.
+func (t *Timeout) ApplyDefaults(defaults Timeout) {
+	utils.SetIfEmpty(&t.Total, defaults.Total)
+	utils.SetIfEmpty(&t.Resolve, defaults.Resolve)
+	utils.SetIfEmpty(&t.Download, defaults.Download)
+	utils.SetIfEmpty(&t.Install, defaults.Install)
+	utils.SetIfEmpty(&t.Post, defaults.Post)
+	utils.SetIfEmpty(&t.VersionCheck, defaults.VersionCheck)
+}
+
+// Validate checks that none of the limits are negative, reporting all negative ones in the order of the fields.
+func (t Timeout) Validate() error {
+	var errs []error
+
+	for _, limit := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"total", t.Total},
+		{"resolve", t.Resolve},
+		{"download", t.Download},
+		{"install", t.Install},
+		{"post", t.Post},
+		{"version-check", t.VersionCheck},
+	} {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("timeout: %s must not be negative, got %s", limit.name, limit.value))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -1,12 +1,15 @@
 package tools
 
 import (
+	"time"
+
 	"github.com/fatih/structs"
 
 	"github.com/idelchi/godyl/internal/detect"
 	"github.com/idelchi/godyl/internal/match"
 	"github.com/idelchi/godyl/internal/tools/sources"
 	"github.com/idelchi/godyl/internal/tools/sources/command"
+	"github.com/idelchi/godyl/internal/tools/sources/common"
 	"github.com/idelchi/godyl/pkg/env"
 	"github.com/idelchi/godyl/pkg/unmarshal"
 	"github.com/idelchi/godyl/pkg/utils"
@@ -32,6 +35,8 @@ type Tool struct {
 	Exe Exe
 	// Platform defines the platform-specific details for the tool, including OS and architecture constraints.
 	Platform detect.Platform
+	// Emulation lists the architectures that can be run through emulation, per platform.
+	Emulation Emulation
 	// Aliases represent alternative names or shortcuts for the tool.
 	Aliases Aliases
 	// Values contains custom values or variables used in the tool's configuration.
@@ -44,6 +49,9 @@ type Tool struct {
 	Source sources.Source
 	// Tags are labels or markers that can be used to categorize or filter the tool.
 	Tags Tags
+	// DependsOn lists the names of the tools which must be installed before this one,
+	// e.g. as its post commands run them.
+	DependsOn unmarshal.SingleOrSlice[string] `mapstructure:"depends-on" yaml:"depends-on"`
 	// Strategy defines how the tool is deployed, fetched, or managed (e.g., download strategies, handling retries).
 	Strategy Strategy
 	// Extensions lists additional files or behaviors that are tied to the tool.
@@ -55,14 +63,45 @@ type Tool struct {
 	// Mode defines the operating mode for the tool, potentially controlling behavior such as silent mode or verbose
 	// mode.
 	Mode Mode
+	// Versions configures side-by-side installations of several versions of the tool.
+	Versions Versions
+	// Backups is the number of previously installed executables kept when replacing them, for rolling back.
+	// Zero or a negative number disables backups. Unset uses the default.
+	Backups *int
+	// Extract defines how the download is extracted into the output folder in `extract` mode.
+	Extract common.ExtractOptions
+	// Timeout limits the time spent on the tool, in total and per phase.
+	Timeout Timeout
 	// Settings contains custom settings or options that modify the behavior of the tool.
 	Settings Settings
 	// Env defines the environment variables that are applied when running the tool.
 	Env env.Env
 	// Check defines a set of instructions for verifying the tool's integrity or functionality.
 	Check Checker
+	// Files maps patterns of additional files in the download, such as man pages or completions, to their role.
+	Files Files
+	// Directories defines the folders into which the additional files are placed, per role.
+	Directories Directories
+	// Completions defines the commands generating shell completions for the installed executable.
+	Completions Completions
+	// Overrides holds partial configurations which are merged over the tool's configuration on matching platforms.
+	Overrides Overrides
 	// NoVerifySSL specifies whether SSL verification should be disabled when fetching the tool.
 	NoVerifySSL bool `json:"-" mapstructure:"-" yaml:"-"`
+	// LockTimeout is the time to wait for other processes holding locks on shared folders.
+	LockTimeout time.Duration `json:"-" mapstructure:"-" yaml:"-"`
+	// Previous is the version of the executable found in the output folder before installing, if queried.
+	Previous string `json:"-" mapstructure:"-" yaml:"-"`
+	// Emulated indicates whether the installed executable runs through emulation.
+	Emulated bool `json:"-" mapstructure:"-" yaml:"-"`
+	// Pruned lists the versions removed after installing, when installing several versions side by side.
+	Pruned []string `json:"-" mapstructure:"-" yaml:"-"`
+	// Dir is the folder against which a relative output folder resolves, instead of the working folder, if set.
+	Dir string `json:"-" mapstructure:"-" yaml:"-"`
+	// Origin is the tools file and position the tool was defined at.
+	Origin Origin `json:"-" mapstructure:"-" yaml:"-"`
+	// Chooser resolves ambiguous asset matches, e.g. by prompting the user.
+	Chooser match.Chooser `json:"-" mapstructure:"-" yaml:"-"`
 }
 
 // UnmarshalYAML implements custom unmarshaling for Tool with KnownFields check.
@@ -90,23 +129,40 @@ func (t *Tool) UnmarshalYAML(value *yaml.Node) error {
 
 // ApplyDefaults applies default values to the Tool configuration.
 // If a field is empty or nil, it is replaced with the corresponding default from the Defaults struct.
+// It returns an error if the tool's overrides are invalid.
 // TODO(Idelchi): Improve - what if someone wants a value to be ""?
-func (t *Tool) ApplyDefaults(d Defaults) {
+func (t *Tool) ApplyDefaults(d Defaults) error {
+	// The platform is needed first, to select the platform-specific overrides.
+	t.Platform.Merge(d.Platform)
+
+	if err := t.ApplyOverrides(); err != nil {
+		return err
+	}
+
 	utils.SetIfEmpty(&t.Output, d.Output)
 	utils.SetIfEmpty(&t.Source.Type, d.Source.Type)
 	utils.SetIfEmpty(&t.Source.Github.Token, d.Source.Github.Token)
 	utils.SetIfEmpty(&t.Strategy, d.Strategy)
 	utils.SetSliceIfNil(&t.Skip, Condition{Condition: "false"})
 	utils.SetIfEmpty(&t.Mode, d.Mode)
+	utils.SetIfEmpty(&t.Versions, d.Versions)
+	utils.SetPointerIfNil(&t.Backups, d.Backups)
 	utils.SetSliceIfNil(&t.Exe.Patterns, d.Exe.Patterns...)
+	for i := range t.Exe.Extra {
+		utils.SetSliceIfNil(&t.Exe.Extra[i].Patterns, d.Exe.Patterns...)
+	}
 	utils.SetSliceIfNil(&t.Extensions, d.Extensions...)
 	utils.SetSliceIfNil(&t.Version.Commands, d.Version.Commands...)
 	utils.SetSliceIfNil(&t.Version.Patterns, d.Version.Patterns...)
+	utils.SetMapIfNil(&t.Emulation, d.Emulation)
+	t.Directories.ApplyDefaults(d.Directories)
+	t.Timeout.ApplyDefaults(d.Timeout)
 	utils.SetMapIfNil(&t.Values, d.Values)
 	utils.DeepMergeMapsWithoutOverwrite(t.Values, d.Values)
 	t.Env.Merge(d.Env)
 
-	// Apply platform-specific defaults and hints.
-	t.Platform.Merge(d.Platform)
+	// Apply platform-specific hints.
 	t.Hints.Append(d.Hints)
+
+	return nil
 }
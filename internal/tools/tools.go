@@ -9,14 +9,13 @@ import (
 	"github.com/idelchi/go-next-tag/pkg/stdin"
 	"github.com/idelchi/godyl/internal/tools/sources"
 	"github.com/idelchi/godyl/pkg/utils"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Tools represents a collection of Tool configurations.
 type Tools []Tool
 
-// Load reads a tool configuration file and loads it into the Tools collection.
+// Load reads a tool configuration file, from disk, stdin (`-`) or an HTTPS URL, and loads it into the Tools collection.
+// Entries of the form `include: <path, glob or URL>` are replaced by the tools of the referenced files.
 // If the configuration is not a YAML file, it assumes a tool is being referenced by name or URL and creates a simple
 // tool entry.
 func (t *Tools) Load(cfg string) (err error) {
@@ -48,9 +47,12 @@ func (t *Tools) Load(cfg string) (err error) {
 		return nil
 	}
 
+	l := loader{loaded: make(map[string]bool)}
+
 	var data []byte
 
-	if cfg == "-" {
+	switch {
+	case cfg == "-":
 		if !stdin.IsPiped() {
 			return errors.New("no data piped to stdin")
 		}
@@ -60,18 +62,33 @@ func (t *Tools) Load(cfg string) (err error) {
 		}
 
 		data = []byte(input)
-	} else {
+	case utils.IsURL(cfg):
+		// Fetch the YAML configuration file, or use the cached copy.
+		l.loaded[cfg] = true
+
+		data, err = fetch(cfg, "")
+		if err != nil {
+			return err
+		}
+	default:
 		// Read the YAML configuration file from disk.
 		input, err := os.ReadFile(cfg)
 		if err != nil {
 			return err
 		}
 
+		absolute, err := filepath.Abs(cfg)
+		if err != nil {
+			return err
+		}
+
+		l.loaded[absolute] = true
+
 		data = input
 	}
 
-	// Unmarshal the YAML content into the Tools collection.
-	err = yaml.Unmarshal(data, t)
+	// Unmarshal the YAML content into the Tools collection, following the includes.
+	*t, err = l.parse(cfg, data)
 	if err != nil {
 		return err
 	}
@@ -1 +1,634 @@
 package tools_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idelchi/godyl/internal/detect"
+	"github.com/idelchi/godyl/internal/match"
+	"github.com/idelchi/godyl/internal/tools"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSaveHintsKeepsUntouchedEntries(t *testing.T) {
+	t.Parallel()
+
+	head := "# Tools for the team\n\n- name: first/tool   # aligned comment\n  tags:   [a,  b]\n\n"
+	middle := "# the middle one\n- name: middle/tool\n  exe:\n    name:    middle\n\n\n  # trailing comment, inside the entry\n  tags: [c]\n\n"
+	tail := "# the end\n"
+
+	content := head +
+		"# by name only\n- second/tool # kept\n\n" +
+		middle +
+		"- name: last/tool\n  hints:\n    pattern: last\n" +
+		tail
+
+	path := filepath.Join(t.TempDir(), "tools.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hints := map[int]match.Hint{
+		1: {Pattern: "second", Must: true},
+		3: {Pattern: "last-again", Must: true},
+	}
+
+	if err := tools.SaveHints(path, hints); err != nil {
+		t.Fatalf("SaveHints() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := string(data)
+
+	for _, untouched := range []string{head, "# by name only\n", middle, tail} {
+		if !strings.Contains(saved, untouched) {
+			t.Errorf("saved file lost %q:\n%s", untouched, saved)
+		}
+	}
+
+	if !strings.HasPrefix(saved, head) || !strings.HasSuffix(saved, tail) {
+		t.Errorf("saved file changed around the edited entries:\n%s", saved)
+	}
+
+	var loaded tools.Tools
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"first/tool":  nil,
+		"second/tool": {"second"},
+		"middle/tool": nil,
+		"last/tool":   {"last", "last-again"},
+	}
+
+	if len(loaded) != len(want) {
+		t.Fatalf("loaded %d tools, want %d", len(loaded), len(want))
+	}
+
+	for _, tool := range loaded {
+		var patterns []string
+		for _, hint := range tool.Hints {
+			patterns = append(patterns, hint.Pattern)
+		}
+
+		if strings.Join(patterns, ",") != strings.Join(want[tool.Name], ",") {
+			t.Errorf("%s: hints = %v, want %v", tool.Name, patterns, want[tool.Name])
+		}
+	}
+}
+
+func TestOverrideMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key      string
+		platform string
+		want     bool
+	}{
+		{key: "linux", platform: "linux-amd64", want: true},
+		{key: "linux", platform: "darwin-amd64", want: false},
+		{key: "linux/amd64", platform: "linux-x86_64", want: true},
+		{key: "linux/amd64", platform: "linux-arm64", want: false},
+		{key: "linux/amd64", platform: "linux-x86_64_v3", want: true},
+		{key: "linux/x86-64-v3", platform: "linux-x86_64_v3", want: true},
+		{key: "linux/x86-64-v3", platform: "linux-x86_64", want: false},
+		{key: "linux/arm", platform: "linux-armv5", want: true},
+		{key: "linux/arm", platform: "linux-armv6", want: true},
+		{key: "linux/arm", platform: "linux-armv7", want: true},
+		{key: "linux/armv7", platform: "linux-armv7", want: true},
+		{key: "linux/armv7", platform: "linux-armv6", want: false},
+		{key: "linux/arm", platform: "linux-arm64", want: false},
+		{key: "linux/amd64/musl", platform: "linux-amd64-musl", want: true},
+		{key: "linux/amd64/musl", platform: "linux-amd64-gnu", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"@"+tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			var platform detect.Platform
+
+			platform.Parse(tt.platform)
+
+			got, err := tools.OverrideMatches(tt.key, platform)
+			if err != nil {
+				t.Fatalf("OverrideMatches() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("OverrideMatches(%q, %q) = %v, want %v", tt.key, tt.platform, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideMatchesInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"plan9", "linux/sparc", "linux/amd64/foo", "linux/amd64/gnu/extra"} {
+		if _, err := tools.OverrideMatches(key, detect.Platform{}); !errors.Is(err, tools.ErrOverride) {
+			t.Errorf("OverrideMatches(%q) error = %v, want %v", key, err, tools.ErrOverride)
+		}
+	}
+}
+
+func TestApplyDefaultsOverrides(t *testing.T) {
+	t.Parallel()
+
+	var defaults tools.Defaults
+	defaults.Platform.Parse("linux-amd64")
+
+	tests := []struct {
+		name   string
+		yaml   string
+		output string
+		err    bool
+	}{
+		{name: "matching", yaml: "name: tool\noutput: bin\noverrides:\n  linux:\n    output: linux\n", output: "linux"},
+		{name: "other platform", yaml: "name: tool\noutput: bin\noverrides:\n  windows:\n    output: windows\n", output: "bin"},
+		{name: "zero value", yaml: "name: tool\noutput: bin\noverrides:\n  linux:\n    output: \"\"\n", output: "bin"},
+		{
+			name: "invalid",
+			yaml: "name: tool\noutput: bin\noverrides:\n  linux:\n    output: linux\n  plan9:\n    output: plan9\n",
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tool tools.Tool
+			if err := yaml.Unmarshal([]byte(tt.yaml), &tool); err != nil {
+				t.Fatal(err)
+			}
+
+			err := tool.ApplyDefaults(defaults)
+			if tt.err {
+				if !errors.Is(err, tools.ErrOverride) {
+					t.Errorf("ApplyDefaults() error = %v, want %v", err, tools.ErrOverride)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyDefaults() error = %v", err)
+			}
+
+			if tool.Output != tt.output {
+				t.Errorf("Output = %q, want %q", tool.Output, tt.output)
+			}
+		})
+	}
+}
+
+func TestEmulationFor(t *testing.T) {
+	t.Parallel()
+
+	var host detect.Platform
+	host.Parse("darwin-arm64")
+
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+		err  bool
+	}{
+		{name: "single", yaml: "darwin/arm64: amd64", want: []string{"amd64"}},
+		{name: "list", yaml: "Darwin/ARM64: [x86_64, 386]", want: []string{"amd64", "386"}},
+		{name: "other platform", yaml: "linux/arm64: amd64", want: nil},
+		{name: "invalid architecture", yaml: "darwin/arm64: [amd64, sparc]", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var emulation tools.Emulation
+			if err := yaml.Unmarshal([]byte(tt.yaml), &emulation); err != nil {
+				t.Fatal(err)
+			}
+
+			architectures, err := emulation.For(host)
+			if (err != nil) != tt.err {
+				t.Fatalf("For() error = %v, want error %t", err, tt.err)
+			}
+
+			var got []string
+			for _, architecture := range architectures {
+				got = append(got, architecture.Type)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("For() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsBackups(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+		want int
+	}{
+		{name: "unset uses the default", yaml: "name: tool", want: 3},
+		{name: "zero disables backups", yaml: "name: tool\nbackups: 0", want: 0},
+		{name: "explicit number is kept", yaml: "name: tool\nbackups: 5", want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tool tools.Tool
+			if err := yaml.Unmarshal([]byte(tt.yaml), &tool); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tool.ApplyDefaults(tools.Defaults{Backups: 3}); err != nil {
+				t.Fatalf("ApplyDefaults() error = %v", err)
+			}
+
+			if tool.Backups == nil || *tool.Backups != tt.want {
+				t.Errorf("Backups = %v, want %d", tool.Backups, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+		want tools.Timeout
+		err  bool
+	}{
+		{name: "scalar", yaml: "timeout: 5m", want: tools.Timeout{Total: 5 * time.Minute}},
+		{
+			name: "struct",
+			yaml: "timeout:\n  total: 10m\n  download: 2m\n  version-check: 5s",
+			want: tools.Timeout{Total: 10 * time.Minute, Download: 2 * time.Minute, VersionCheck: 5 * time.Second},
+		},
+		{name: "unknown field", yaml: "timeout:\n  downlaod: 2m", err: true},
+		{name: "invalid duration", yaml: "timeout: soon", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tool struct {
+				Timeout tools.Timeout
+			}
+
+			err := yaml.Unmarshal([]byte(tt.yaml), &tool)
+			if (err != nil) != tt.err {
+				t.Fatalf("Unmarshal() error = %v, want error %t", err, tt.err)
+			}
+
+			if !tt.err && tool.Timeout != tt.want {
+				t.Errorf("Timeout = %+v, want %+v", tool.Timeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutApplyDefaults(t *testing.T) {
+	t.Parallel()
+
+	timeout := tools.Timeout{Total: 10 * time.Minute, Install: time.Minute}
+
+	timeout.ApplyDefaults(tools.Timeout{Total: time.Hour, Resolve: 30 * time.Second, VersionCheck: 5 * time.Second})
+
+	want := tools.Timeout{
+		Total:        10 * time.Minute,
+		Resolve:      30 * time.Second,
+		Install:      time.Minute,
+		VersionCheck: 5 * time.Second,
+	}
+
+	if timeout != want {
+		t.Errorf("ApplyDefaults() = %+v, want %+v", timeout, want)
+	}
+}
+
+func TestTimeoutValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (tools.Timeout{Total: time.Minute}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	err := tools.Timeout{Post: -time.Second, Resolve: -time.Minute, VersionCheck: -time.Second}.Validate()
+
+	want := "timeout: resolve must not be negative, got -1m0s\n" +
+		"timeout: post must not be negative, got -1s\n" +
+		"timeout: version-check must not be negative, got -1s"
+
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %q", err, want)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		dependencies [][]int
+		want         []int
+	}{
+		{name: "no tools", dependencies: nil, want: nil},
+		{name: "no dependencies", dependencies: [][]int{nil, nil}, want: nil},
+		{name: "chain", dependencies: [][]int{{1}, {2}, nil}, want: nil},
+		{name: "diamond", dependencies: [][]int{{1, 2}, {3}, {3}, nil}, want: nil},
+		{name: "self", dependencies: [][]int{{0}}, want: []int{0, 0}},
+		{name: "pair", dependencies: [][]int{{1}, {0}}, want: []int{0, 1, 0}},
+		{name: "behind a chain", dependencies: [][]int{{1}, {2}, {3}, {1}}, want: []int{1, 2, 3, 1}},
+		{name: "in a later tool", dependencies: [][]int{nil, {2}, {1}}, want: []int{1, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tools.FindCycle(tt.dependencies); !slices.Equal(got, tt.want) {
+				t.Errorf("FindCycle(%v) = %v, want %v", tt.dependencies, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	tool := func(name string, dependsOn ...string) tools.Tool {
+		return tools.Tool{Name: name, DependsOn: dependsOn}
+	}
+
+	tests := []struct {
+		name  string
+		tools tools.Tools
+		want  [][]int
+		err   string
+	}{
+		{
+			name:  "no dependencies",
+			tools: tools.Tools{tool("a"), tool("b")},
+			want:  [][]int{nil, nil},
+		},
+		{
+			name:  "by name",
+			tools: tools.Tools{tool("a", "c", "b"), tool("b"), tool("c", "b")},
+			want:  [][]int{{2, 1}, nil, {1}},
+		},
+		{
+			name:  "all tools of the same name",
+			tools: tools.Tools{tool("a", "b"), tool("b"), tool("b")},
+			want:  [][]int{{1, 2}, nil, nil},
+		},
+		{
+			name:  "unknown name",
+			tools: tools.Tools{tool("a", "missing")},
+			err:   `tool "a" depends on "missing", which is not in the tools`,
+		},
+		{
+			name:  "cycle",
+			tools: tools.Tools{tool("a", "b"), tool("b", "c"), tool("c", "a")},
+			err:   "dependency cycle: a -> b -> c -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.tools.Dependencies()
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("Dependencies() error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Dependencies() error = %v", err)
+			}
+
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("Dependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{expression: "a", want: "a"},
+		{expression: "  a  ", want: "a"},
+		{expression: "!a", want: "!a"},
+		{expression: "!!a", want: "!!a"},
+		{expression: "a && b || c", want: "(a && b) || c"},
+		{expression: "a || b && c", want: "a || (b && c)"},
+		{expression: "!a && b", want: "!a && b"},
+		{expression: "!a || !b && c", want: "!a || (!b && c)"},
+		{expression: "a&&b||c&&d", want: "(a && b) || (c && d)"},
+		{expression: "(a || b) && c", want: "(a || b) && c"},
+		{expression: "!(a || b)", want: "!(a || b)"},
+		{expression: "((a))", want: "a"},
+		{expression: "a && (b || (c && !d))", want: "a && (b || (c && !d))"},
+		{expression: "", wantErr: true},
+		{expression: "   ", wantErr: true},
+		{expression: "()", wantErr: true},
+		{expression: "a & b", wantErr: true},
+		{expression: "a | b", wantErr: true},
+		{expression: "a &", wantErr: true},
+		{expression: "|a", wantErr: true},
+		{expression: "a &&", wantErr: true},
+		{expression: "|| a", wantErr: true},
+		{expression: "!", wantErr: true},
+		{expression: "a b", wantErr: true},
+		{expression: "(a", wantErr: true},
+		{expression: "a)", wantErr: true},
+		{expression: "((a) || b", wantErr: true},
+		{expression: "(a || b))", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tools.ParseTagExpression(tt.expression)
+
+			if tt.wantErr {
+				if !errors.Is(err, tools.ErrTagExpression) {
+					t.Fatalf("ParseTagExpression(%q) error = %v, want %v", tt.expression, err, tools.ErrTagExpression)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTagExpression(%q) error = %v", tt.expression, err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("ParseTagExpression(%q) = %q, want %q", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagExpressionMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		tags       tools.Tags
+		want       bool
+	}{
+		{expression: "a", tags: tools.Tags{"a"}, want: true},
+		{expression: "a", tags: nil, want: false},
+		{expression: "!a", tags: tools.Tags{"b"}, want: true},
+		{expression: "a && b", tags: tools.Tags{"a"}, want: false},
+		{expression: "a && b", tags: tools.Tags{"b", "a"}, want: true},
+		{expression: "a || b && c", tags: tools.Tags{"a"}, want: true},
+		{expression: "(a || b) && c", tags: tools.Tags{"a"}, want: false},
+		{expression: "!a && b", tags: tools.Tags{"b"}, want: true},
+		{expression: "!(a && b)", tags: tools.Tags{"a", "b"}, want: false},
+		{expression: "!(a && b)", tags: tools.Tags{"a"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression+"@"+strings.Join(tt.tags, ","), func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := tools.ParseTagExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseTagExpression(%q) error = %v", tt.expression, err)
+			}
+
+			if got := expression.Matches(tt.tags); got != tt.want {
+				t.Errorf("%q.Matches(%v) = %v, want %v", tt.expression, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expressions []string
+		include     []string
+		exclude     []string
+		wantErr     bool
+	}{
+		{name: "none", expressions: nil},
+		{name: "empty ones are ignored", expressions: []string{"", "  "}},
+		{name: "plain tags", expressions: []string{"a", "b", "!c"}, include: []string{"a", "b"}, exclude: []string{"!c"}},
+		{name: "negated group is an exclusion", expressions: []string{"!(a || b)"}, exclude: []string{"!(a || b)"}},
+		{name: "negation within is an inclusion", expressions: []string{"!a && b"}, include: []string{"!a && b"}},
+		{name: "invalid", expressions: []string{"a", "b &"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := tools.ParseTagFilter(tt.expressions)
+
+			if tt.wantErr {
+				if !errors.Is(err, tools.ErrTagExpression) {
+					t.Fatalf("ParseTagFilter(%q) error = %v, want %v", tt.expressions, err, tools.ErrTagExpression)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTagFilter(%q) error = %v", tt.expressions, err)
+			}
+
+			if got := expressionStrings(filter.Include); !slices.Equal(got, tt.include) {
+				t.Errorf("Include = %q, want %q", got, tt.include)
+			}
+
+			if got := expressionStrings(filter.Exclude); !slices.Equal(got, tt.exclude) {
+				t.Errorf("Exclude = %q, want %q", got, tt.exclude)
+			}
+		})
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := tools.ParseTagFilter([]string{"a", "b && c", "!(d || e)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tags     tools.Tags
+		includes bool
+		excludes bool
+	}{
+		{tags: tools.Tags{"a"}, includes: true, excludes: false},
+		{tags: tools.Tags{"b"}, includes: false, excludes: false},
+		{tags: tools.Tags{"b", "c"}, includes: true, excludes: false},
+		{tags: tools.Tags{"a", "d"}, includes: true, excludes: true},
+		{tags: tools.Tags{"e"}, includes: false, excludes: true},
+	}
+
+	for _, tt := range tests {
+		if got := filter.Includes(tt.tags); got != tt.includes {
+			t.Errorf("Includes(%v) = %v, want %v", tt.tags, got, tt.includes)
+		}
+
+		if got := filter.Excludes(tt.tags); got != tt.excludes {
+			t.Errorf("Excludes(%v) = %v, want %v", tt.tags, got, tt.excludes)
+		}
+	}
+
+	if empty := (tools.TagFilter{}); !empty.Includes(nil) || empty.Excludes(nil) {
+		t.Errorf("an empty filter must include and not exclude any tool")
+	}
+}
+
+// expressionStrings returns the canonical forms of the expressions.
+func expressionStrings(expressions []tools.TagExpression) []string {
+	var strs []string
+
+	for _, expression := range expressions {
+		strs = append(strs, expression.String())
+	}
+
+	return strs
+}
@@ -1,10 +1,11 @@
 package tools
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
-	"strings"
 
 	"github.com/go-playground/validator/v10"
 
@@ -38,15 +39,40 @@ var (
 	ErrSkipped = fmt.Errorf("tool skipped")
 	// ErrFailed indicates that the tool has failed to install or resolve.
 	ErrFailed = fmt.Errorf("tool failed")
+	// ErrDependency indicates that the tool has been skipped as a tool it depends on failed.
+	ErrDependency = fmt.Errorf("dependency failed")
+	// ErrTimeout indicates that a phase of the tool did not complete within its time limit.
+	ErrTimeout = common.ErrTimeout
 )
 
-// Resolve attempts to resolve the tool's source and strategy based on the provided tags.
+// Resolve attempts to resolve the tool's source and strategy, skipping it if its tags do not pass the filter.
 // It handles fallbacks and applies templating to the tool's fields as needed.
-func (t *Tool) Resolve(withTags, withoutTags []string) error {
+// The context cancels any requests to the sources.
+func (t *Tool) Resolve(ctx context.Context, tags TagFilter) error {
 	if len(t.Name) == 0 {
 		return fmt.Errorf("%w: tool name is empty", ErrFailed)
 	}
 
+	if err := t.Overrides.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Files.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Completions.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Extract.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Timeout.Validate(); err != nil {
+		return err
+	}
+
 	// Normalize values to ensure consistency in the .Values map.
 	t.Values = utils.NormalizeMap(t.Values)
 
@@ -65,9 +91,17 @@ func (t *Tool) Resolve(withTags, withoutTags []string) error {
 		t.Strategy = Force
 	}
 
+	if err := t.Versions.Validate(t.Mode); err != nil {
+		return err
+	}
+
 	// Save the path for templating later.
 	path := t.Path
 
+	// The aliases of the primary executable are the tool's aliases.
+	t.Aliases = append(t.Aliases, t.Exe.Aliases...)
+	t.Exe.Aliases = nil
+
 	t.Extensions = slices.Compact(t.Extensions)
 	t.Aliases = slices.Compact(t.Aliases)
 
@@ -78,42 +112,53 @@ func (t *Tool) Resolve(withTags, withoutTags []string) error {
 		return err
 	}
 
+	if t.Dir != "" && !filepath.IsAbs(t.Output) {
+		t.Output = filepath.Join(t.Dir, t.Output)
+	}
+
 	t.Fallbacks = slices.Compact(t.Fallbacks)
 
 	// Build the fallback sources from the primary source type and additional fallbacks.
 	fallbacks := append([]sources.Type{t.Source.Type}, t.Fallbacks...)
 
-	var lastErr error
-	// Try resolving with each fallback in order.
-	for _, fallback := range slices.Compact(fallbacks) {
-		if fallback == sources.RUST {
-			// Skip Rust fallback for now.
-			continue
+	return common.WithTimeout(ctx, "resolve", t.Timeout.Resolve, func(ctx context.Context) error {
+		var lastErr error
+		// Try resolving with each fallback in order.
+		for _, fallback := range slices.Compact(fallbacks) {
+			if fallback == sources.RUST {
+				// Skip Rust fallback for now.
+				continue
+			}
+
+			if err := t.tryResolveFallback(ctx, fallback, path, tags); ErrCausesEarlyReturn(err) {
+				return err
+			} else if err != nil {
+				lastErr = err
+
+				// Other fallbacks would fail the same way.
+				if ctx.Err() != nil {
+					return err
+				}
+
+				continue // Move on to the next fallback.
+			}
+
+			return nil // Success, no need to try further fallbacks.
 		}
 
-		if err := t.tryResolveFallback(fallback, path, withTags, withoutTags); ErrCausesEarlyReturn(err) {
-			return err
-		} else if err != nil {
-			lastErr = err
-
-			continue // Move on to the next fallback.
-		}
-
-		return nil // Success, no need to try further fallbacks.
-	}
-
-	// If all fallbacks fail, return the last encountered error.
-	return lastErr
+		// If all fallbacks fail, return the last encountered error.
+		return lastErr
+	})
 }
 
 // CheckSkipConditions verifies whether the tool should be skipped based on its tags or strategy.
-func (t *Tool) CheckSkipConditions(withTags, withoutTags []string) error {
-	if !t.Tags.Has(withTags) {
-		return fmt.Errorf("%w: %v: tool tags: %v", ErrDoesNotHaveTags, withTags, t.Tags)
+func (t *Tool) CheckSkipConditions(tags TagFilter) error {
+	if !tags.Includes(t.Tags) {
+		return fmt.Errorf("%w: %v: tool tags: %v", ErrDoesNotHaveTags, tags.Include, t.Tags)
 	}
 
-	if !t.Tags.HasNot(withoutTags) {
-		return fmt.Errorf("%w: %v: tool tags: %v", ErrDoesHaveTags, withoutTags, t.Tags)
+	if tags.Excludes(t.Tags) {
+		return fmt.Errorf("%w: %v: tool tags: %v", ErrDoesHaveTags, tags.Exclude, t.Tags)
 	}
 
 	if err := t.Strategy.Check(t); err != nil {
@@ -128,12 +173,17 @@ func (t *Tool) CheckSkipConditions(withTags, withoutTags []string) error {
 }
 
 // tryResolveFallback attempts to resolve a tool using a specific fallback source type.
-func (t *Tool) tryResolveFallback(fallback sources.Type, path string, withTags, withoutTags []string) error {
+func (t *Tool) tryResolveFallback(
+	ctx context.Context,
+	fallback sources.Type,
+	path string,
+	tags TagFilter,
+) error {
 	// Append the tool's name as a tag.
 	t.Tags.Append(t.Name)
 
 	// Check if the tool should be skipped based on its conditions.
-	if err := t.CheckSkipConditions(withTags, withoutTags); err != nil {
+	if err := t.CheckSkipConditions(tags); err != nil {
 		return err
 	}
 
@@ -161,13 +211,13 @@ func (t *Tool) tryResolveFallback(fallback sources.Type, path string, withTags,
 	utils.SetIfEmpty(&t.Exe.Name, t.Name)
 
 	// Re-check skip conditions after applying templates.
-	if err := t.CheckSkipConditions(withTags, withoutTags); err != nil {
+	if err := t.CheckSkipConditions(tags); err != nil {
 		return err
 	}
 
 	// Retrieve the tool's version from the installer if it is not already set.
 	if utils.IsEmpty(t.Version.Version) {
-		if err := populator.Version(t.Name); err != nil {
+		if err := populator.Version(ctx, t.Name); err != nil {
 			return err
 		}
 	}
@@ -183,9 +233,16 @@ func (t *Tool) tryResolveFallback(fallback sources.Type, path string, withTags,
 		hints := t.Hints
 		hints.Add(ExtensionsToHint(t.Extensions))
 
-		if err := populator.Path(t.Name, nil, t.Version.Version, match.Requirements{
+		emulated, err := t.Emulation.For(t.Platform)
+		if err != nil {
+			return err
+		}
+
+		if err := populator.Path(ctx, t.Name, nil, t.Version.Version, match.Requirements{
 			Platform: t.Platform,
 			Hints:    hints,
+			Emulated: emulated,
+			Chooser:  t.Chooser,
 		}); err != nil {
 			return err
 		}
@@ -193,13 +250,12 @@ func (t *Tool) tryResolveFallback(fallback sources.Type, path string, withTags,
 	utils.SetIfEmpty(&t.Path, populator.Get("path"))
 	utils.SetIfEmpty(&t.Path, path)
 
-	// Append platform-specific file extension to the executable name.
-	if !strings.HasSuffix(t.Exe.Name, t.Platform.Extension.String()) {
-		t.Exe.Name += t.Platform.Extension.String()
-	}
+	// Append platform-specific file extension to the executable names and set the patterns for finding them.
+	t.Exe.Complete(t.Platform.Extension.String())
 
-	// Set patterns for finding the executable.
-	utils.SetSliceIfNil(&t.Exe.Patterns, fmt.Sprintf("^%s$", t.Exe.Name))
+	for i := range t.Exe.Extra {
+		t.Exe.Extra[i].Complete(t.Platform.Extension.String())
+	}
 
 	// Append platform-specific extensions to aliases.
 	for i, alias := range t.Aliases {
@@ -207,7 +263,7 @@ func (t *Tool) tryResolveFallback(fallback sources.Type, path string, withTags,
 	}
 
 	// Attempt to upgrade the tool using the current strategy.
-	if err := t.Strategy.Upgrade(t); err != nil {
+	if err := t.Strategy.Upgrade(ctx, t); err != nil {
 		return err
 	}
 
@@ -224,30 +280,90 @@ func (t *Tool) Validate() error {
 	return nil
 }
 
-// Exists checks if the tool's executable already exists in the output path.
+// Exists checks if all of the tool's executables already exist in the output path.
 func (t *Tool) Exists() bool {
-	f := file.NewFile(t.Output, t.Exe.Name)
-	return f.Exists() && f.IsFile()
+	for _, name := range t.Exe.Names() {
+		f := file.NewFile(t.Output, name)
+		if !f.Exists() || !f.IsFile() {
+			return false
+		}
+	}
+
+	return true
 }
 
-// Download downloads the tool using its configured source and installer.
-func (t *Tool) Download() (string, file.File, error) {
+// Download downloads the tool using its configured source and installer, until the context is cancelled.
+func (t *Tool) Download(ctx context.Context) (string, file.File, error) {
 	installer, err := t.Source.Installer()
 	if err != nil {
 		return "", "", err
 	}
 
+	emulated, err := t.Emulation.For(t.Platform)
+	if err != nil {
+		return "", "", err
+	}
+
 	data := common.InstallData{
-		Path:        t.Path,
-		Name:        t.Name,
-		Exe:         t.Exe.Name,
-		Patterns:    t.Exe.Patterns,
-		Output:      t.Output,
-		Aliases:     t.Aliases,
-		Mode:        t.Mode.String(),
-		Env:         t.Env,
-		NoVerifySSL: t.NoVerifySSL,
-	}
-
-	return installer.Install(data)
+		Path:            t.Path,
+		Name:            t.Name,
+		Exe:             t.Exe.Name,
+		Patterns:        t.Exe.Patterns,
+		Output:          t.Output,
+		Aliases:         t.Aliases,
+		Mode:            t.Mode.String(),
+		Env:             t.Env,
+		NoVerifySSL:     t.NoVerifySSL,
+		LockTimeout:     t.LockTimeout,
+		DownloadTimeout: t.Timeout.Download,
+		Platform:        t.Platform,
+		Emulated:        emulated,
+		Hints:           t.Hints.Content(),
+		Extract:         t.Extract,
+	}
+
+	if t.Backups != nil {
+		data.Backups = *t.Backups
+	}
+
+	if data.Files, err = t.FileRules(); err != nil {
+		return "", "", err
+	}
+
+	// Versioned installations place the executables into the folder of the version.
+	if t.Versions.Enabled {
+		if data.ExeOutput, err = t.InstalledVersions().Path(t.Version.Version); err != nil {
+			return "", "", fmt.Errorf("installing %q side by side: %w", t.Name, err)
+		}
+	}
+
+	for _, extra := range t.Exe.Extra {
+		data.Extra = append(data.Extra, common.Executable{
+			Name:     extra.Name,
+			Patterns: extra.Patterns,
+			Aliases:  extra.Aliases,
+		})
+	}
+
+	var output string
+	var found file.File
+
+	err = common.WithTimeout(ctx, "install", t.Timeout.Install, func(ctx context.Context) (err error) {
+		output, found, err = installer.Install(ctx, data)
+
+		return err
+	})
+
+	// The installer may have fallen back to another asset.
+	if path := installer.Get("path"); path != "" {
+		t.Path = path
+	}
+
+	t.Emulated = installer.Get("emulated") == "true"
+
+	if err == nil && t.Versions.Enabled {
+		t.Pruned, err = t.ActivateVersion()
+	}
+
+	return output, found, err
 }
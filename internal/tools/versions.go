@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/fatih/structs"
+
+	"github.com/idelchi/godyl/internal/versions"
+	"github.com/idelchi/godyl/pkg/unmarshal"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Versions configures side-by-side installations of several versions of a tool.
+type Versions struct {
+	// Enabled installs each version into its own folder below the output folder,
+	// with the executables in the output folder linking to the active version.
+	Enabled bool
+	// Keep is the number of versions to retain, removing the least recently installed ones.
+	// The active version is always kept. Zero keeps all versions.
+	Keep int
+}
+
+// UnmarshalYAML implements custom unmarshaling for Versions,
+// allowing the YAML to either provide just a boolean to enable it, or the full Versions structure.
+func (v *Versions) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&v.Enabled)
+	}
+
+	type raw Versions
+
+	return unmarshal.DecodeWithOptionalKnownFields(value, (*raw)(v), true, structs.New(v).Name())
+}
+
+// Validate checks that versioned installations are only used with the `find` mode.
+func (v Versions) Validate(mode Mode) error {
+	if v.Keep < 0 {
+		return fmt.Errorf("versions: keep must not be negative, got %d", v.Keep)
+	}
+
+	if v.Enabled && mode != Find {
+		return fmt.Errorf("versions: only supported in %q mode, got %q", Find, mode)
+	}
+
+	return nil
+}
+
+// InstalledVersions returns the installed versions of the tool.
+func (t *Tool) InstalledVersions() versions.Versions {
+	return versions.New(t.Output, t.Name)
+}
+
+// ActivateVersion makes the installed version of the tool active and prunes old versions.
+// It returns the removed versions.
+func (t *Tool) ActivateVersion() ([]string, error) {
+	installed := t.InstalledVersions()
+
+	if err := installed.Use(t.Version.Version); err != nil {
+		return nil, err
+	}
+
+	return installed.Prune(t.Versions.Keep)
+}
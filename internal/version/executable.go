@@ -45,11 +45,12 @@ func (e Executable) Command(ctx context.Context, cmdArgs []string) (string, erro
 // ParseVersion attempts to parse the version of the executable using the provided Version object.
 // It iterates over predefined command strategies and tries to parse the version from the command output.
 // If successful, it sets the Version field of Executable; otherwise, it returns an error.
-func (e *Executable) ParseVersion(version *Version) error {
+// The commands are stopped when the context is cancelled.
+func (e *Executable) ParseVersion(ctx context.Context, version *Version) error {
 	timeout := 60 * time.Second
 
 	// Create a context with a timeout to prevent hanging
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	var errs []error
@@ -0,0 +1,4 @@
+// Package versions manages side-by-side installations of several versions of a tool.
+// Each version is installed into its own folder, and the executables in the output folder
+// link to the active version, so that switching versions is a single atomic operation.
+package versions
@@ -0,0 +1,250 @@
+package versions
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/idelchi/godyl/pkg/file"
+)
+
+// Folder is the folder, relative to the output folder, in which the versions are installed.
+const Folder = ".versions"
+
+// current is the file within the folder of a tool that records its active version.
+const current = "current"
+
+var (
+	// ErrVersion is returned when a version cannot be used as the name of a folder.
+	ErrVersion = errors.New("invalid version")
+	// ErrNotInstalled is returned when switching to a version which is not installed.
+	ErrNotInstalled = errors.New("version is not installed")
+)
+
+// Versions manages the installed versions of a tool within an output folder.
+type Versions struct {
+	output string
+	name   string
+}
+
+// New returns the versions of the named tool, installed into the output folder.
+func New(output, name string) Versions {
+	return Versions{output: output, name: name}
+}
+
+// Dir returns the folder holding all versions of the tool.
+func (v Versions) Dir() string {
+	return filepath.Join(v.output, Folder, v.name)
+}
+
+// Path returns the folder into which the version is installed.
+func (v Versions) Path(version string) (string, error) {
+	if version == "" || version == current || strings.ContainsAny(version, `/\`) || !filepath.IsLocal(version) {
+		return "", fmt.Errorf("%w: %q", ErrVersion, version)
+	}
+
+	return filepath.Join(v.Dir(), version), nil
+}
+
+// Active returns the active version, or an empty string if no version is active.
+func (v Versions) Active() (string, error) {
+	path := filepath.Join(v.Dir(), current)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	// Installations prior to recording the active version in a file link to it instead.
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return os.Readlink(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// List returns the installed versions, the most recently installed first.
+func (v Versions) List() ([]string, error) {
+	entries, err := os.ReadDir(v.Dir())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	type installed struct {
+		version string
+		time    int64
+	}
+
+	var versions []installed
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, installed{version: entry.Name(), time: info.ModTime().UnixNano()})
+	}
+
+	slices.SortStableFunc(versions, func(a, b installed) int {
+		return cmp.Compare(b.time, a.time)
+	})
+
+	list := make([]string, len(versions))
+	for i, installed := range versions {
+		list[i] = installed.version
+	}
+
+	return list, nil
+}
+
+// Use makes the version active.
+// Each entry of the version (executables and aliases) is linked into the output folder (copied on Windows),
+// and the active version is recorded, all moved into place together. Links to entries that no longer exist
+// are removed.
+func (v Versions) Use(version string) error {
+	path, err := v.Path(version)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			installed, _ := v.List()
+
+			return fmt.Errorf("%w: %s@%s, installed: %v", ErrNotInstalled, v.name, version, installed)
+		}
+
+		return err
+	}
+
+	// The links are absolute, as the copies made in their place on Windows are read from the working directory.
+	if path, err = filepath.Abs(path); err != nil {
+		return err
+	}
+
+	var transaction file.Transaction
+
+	names := make(map[string]bool)
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		names[entry.Name()] = true
+
+		link := file.NewFile(v.output, entry.Name())
+
+		if err := transaction.Symlink(file.NewFile(path, entry.Name()), link); err != nil {
+			transaction.Discard()
+
+			return fmt.Errorf("linking %q: %w", entry.Name(), err)
+		}
+	}
+
+	if err := transaction.Write(file.NewFile(v.Dir(), current), []byte(version+"\n"), 0o644); err != nil {
+		transaction.Discard()
+
+		return fmt.Errorf("switching %q to version %q: %w", v.name, version, err)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("switching %q to version %q: %w", v.name, version, err)
+	}
+
+	return v.removeDangling(names)
+}
+
+// Prune removes the least recently installed versions, keeping the given number of versions
+// as well as the active one. It returns the removed versions. Zero keeps all versions.
+func (v Versions) Prune(keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	installed, err := v.List()
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := v.Active()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+
+	for i, version := range installed {
+		if i < keep || version == active {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(v.Dir(), version)); err != nil {
+			return removed, fmt.Errorf("removing version %q of %q: %w", version, v.name, err)
+		}
+
+		removed = append(removed, version)
+	}
+
+	return removed, nil
+}
+
+// removeDangling removes the links in the output folder into the tool's versions
+// whose entry does not exist in the active version.
+func (v Versions) removeDangling(active map[string]bool) error {
+	dir, err := filepath.Abs(v.Dir())
+	if err != nil {
+		return err
+	}
+
+	// Installations prior to recording the active version in a file link through the `current` folder link.
+	through := filepath.Join(Folder, v.name, current)
+
+	entries, err := os.ReadDir(v.output)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Type()&fs.ModeSymlink == 0 || active[entry.Name()] {
+			continue
+		}
+
+		link := filepath.Join(v.output, entry.Name())
+
+		target, err := os.Readlink(link)
+		if err != nil || (filepath.Dir(target) != through && !strings.HasPrefix(target, dir+string(filepath.Separator))) {
+			continue
+		}
+
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("removing stale link %q: %w", link, err)
+		}
+	}
+
+	return nil
+}
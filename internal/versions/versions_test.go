@@ -0,0 +1 @@
+package versions_test
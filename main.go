@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "embed"
 
@@ -19,9 +22,17 @@ var defaultsFile []byte
 var toolsFile []byte
 
 func main() {
+	// Interrupts cancel the running installations, a second one terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	app := commands.NewApp(version, defaultsFile, toolsFile)
-	if err := app.Run(); err != nil {
+	if err := app.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(1)
+		os.Exit(commands.ExitCode(err))
 	}
 }
@@ -12,13 +12,14 @@
 //	package main
 //
 //	import (
+//	    "context"
 //	    "log"
 //	    "github.com/idelchi/godyl/pkg/download"
 //	)
 //
 //	func main() {
 //	    d := download.New()
-//	    file, err := d.Download("https://example.com/file.zip", "/path/to/output")
+//	    file, err := d.Download(context.Background(), "https://example.com/file.zip", "/path/to/output")
 //	    if err != nil {
 //	        log.Fatal(err)
 //	    }
@@ -64,9 +65,10 @@ func New() *Downloader {
 
 // Download fetches a file from the given URL and saves it to the specified output path.
 // If the file is an archive, it will be extracted to the output directory.
+// The download is aborted when the context is cancelled, or after the context timeout.
 // It returns the destination path of the downloaded file (or folder) and any error encountered.
-func (d Downloader) Download(url, output string) (file.File, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), d.ContextTimeout)
+func (d Downloader) Download(ctx context.Context, url, output string) (file.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.ContextTimeout)
 	defer cancel()
 
 	httpClient := cleanhttp.DefaultClient()
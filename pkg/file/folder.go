@@ -154,6 +154,51 @@ func (f Folder) FindFile(criteria ...CriteriaFunc) (File, error) {
 	return file, nil
 }
 
+// FindFiles searches for all files in the Folder that match the provided criteria.
+// It returns the files in walk order or an error if none are found.
+func (f Folder) FindFiles(criteria ...CriteriaFunc) (Files, error) {
+	var files Files
+
+	err := filepath.Walk(f.Path(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if File(path).IsDir() {
+			return nil // Skip directories
+		}
+
+		path, err = filepath.Rel(f.Path(), path)
+		if err != nil {
+			return err
+		}
+
+		// Check if the file matches all criteria
+		for _, criterion := range criteria {
+			matches, err := criterion(File(path))
+			if err != nil {
+				return err
+			}
+			if !matches {
+				return nil // Skip this file if it doesn't match all criteria
+			}
+		}
+
+		files = append(files, NewFile(f.Path(), path))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking folder %s: %w", f.Path(), err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no file found matching all criteria in folder %s", ErrNotFound, f.Path())
+	}
+
+	return files, nil
+}
+
 // ListFolders returns a slice of Folders representing all subdirectories
 // within the current Folder.
 func (f Folder) ListFolders() ([]Folder, error) {
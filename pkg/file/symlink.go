@@ -24,3 +24,8 @@ func (f File) Symlink(symlinks ...File) error {
 
 	return nil
 }
+
+// stageLink creates a symbolic link to the file at the temporary path.
+func stageLink(f, _ File, temporary string) error {
+	return os.Symlink(f.Name(), temporary)
+}
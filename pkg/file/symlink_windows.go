@@ -20,3 +20,8 @@ func (f File) Symlink(symlinks ...File) error {
 
 	return nil
 }
+
+// stageLink copies the file, or its staged copy, to the temporary path.
+func stageLink(_, staged File, temporary string) error {
+	return staged.Copy(File(temporary))
+}
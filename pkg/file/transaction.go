@@ -0,0 +1,303 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Transaction stages files next to their targets, and moves all of them into place on Commit.
+// Until then the targets are left untouched, so that an interrupted installation never leaves
+// a partially written file behind. If committing fails, the targets already replaced are restored.
+type Transaction struct {
+	staged  []staged
+	removed []File
+}
+
+// staged is a temporary file waiting to be renamed onto its target.
+type staged struct {
+	temporary string
+	target    File
+}
+
+// replaced is a target moved into place, along with a link to its previous content, if any.
+type replaced struct {
+	target   File
+	previous string
+}
+
+// Copy stages a copy of the source as the target, with the given permissions.
+// The copy is flushed to disk before returning.
+func (t *Transaction) Copy(source, target File, mode fs.FileMode) error {
+	in, err := os.Open(t.source(source))
+	if err != nil {
+		return fmt.Errorf("opening source file: %w", err)
+	}
+	defer in.Close()
+
+	if err := t.stage(target, mode, in); err != nil {
+		return fmt.Errorf("copying %q to %q: %w", source, target, err)
+	}
+
+	return nil
+}
+
+// Write stages the data as the content of the target, with the given permissions.
+// The content is flushed to disk before returning.
+func (t *Transaction) Write(target File, data []byte, mode fs.FileMode) error {
+	if err := t.stage(target, mode, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+
+	return nil
+}
+
+// stage writes the content to a temporary file next to the target, to be moved into place on Commit.
+func (t *Transaction) stage(target File, mode fs.FileMode, content io.Reader) error {
+	out, err := os.CreateTemp(target.Dir().Path(), "."+filepath.Base(target.String())+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("staging %q: %w", target, err)
+	}
+
+	t.staged = append(t.staged, staged{temporary: out.Name(), target: target})
+
+	if _, err := io.Copy(out, content); err != nil {
+		out.Close()
+
+		return err
+	}
+
+	if err := out.Chmod(mode); err != nil {
+		out.Close()
+
+		return fmt.Errorf("setting permissions of %q: %w", target, err)
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+
+		return fmt.Errorf("syncing %q: %w", target, err)
+	}
+
+	return out.Close()
+}
+
+// Symlink stages symbolic links (or copies on Windows) to the file, as File.Symlink does.
+// The file may itself be staged in the transaction.
+func (t *Transaction) Symlink(f File, links ...File) error {
+	for _, link := range links {
+		if link.Name() == f.Name() {
+			continue
+		}
+
+		if err := t.link(f, File(t.source(f)), link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Link stages a symbolic link with the given destination, as read from an existing link.
+// A relative destination is resolved against the folder of the link, which on Windows
+// is where the copy made instead is read from. The destination may itself be staged in the transaction.
+func (t *Transaction) Link(destination string, link File) error {
+	resolved := destination
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(link.String()), destination)
+	}
+
+	return t.link(File(destination), File(t.source(File(resolved))), link)
+}
+
+// link stages a symbolic link to the file, or a copy of its staged content on Windows.
+func (t *Transaction) link(f, content, link File) error {
+	temporary, err := t.reserve(link)
+	if err != nil {
+		return err
+	}
+
+	t.staged = append(t.staged, staged{temporary: temporary, target: link})
+
+	if err := stageLink(f, content, temporary); err != nil {
+		return fmt.Errorf("creating symlink for %q: %w", link, err)
+	}
+
+	return nil
+}
+
+// Remove stages the removal of the files, once the staged files have been moved into place.
+// Files which are staged in the transaction, or do not exist, are skipped.
+func (t *Transaction) Remove(files ...File) {
+	for _, f := range files {
+		if t.source(f) == f.String() && !slices.Contains(t.removed, f) {
+			t.removed = append(t.removed, f)
+		}
+	}
+}
+
+// Commit renames the staged files onto their targets, and then removes the files staged for removal.
+// If any step fails, the targets already replaced or removed are restored and the remaining staged files are removed.
+func (t *Transaction) Commit() error {
+	var done []replaced
+
+	for _, s := range t.staged {
+		previous, err := keep(s.target)
+		if err != nil {
+			return t.abort(done, fmt.Errorf("keeping previous %q: %w", s.target, err))
+		}
+
+		if err := os.Rename(s.temporary, s.target.String()); err != nil {
+			if previous != "" {
+				os.Remove(previous)
+			}
+
+			return t.abort(done, fmt.Errorf("moving %q into place: %w", s.target, err))
+		}
+
+		done = append(done, replaced{target: s.target, previous: previous})
+	}
+
+	for _, f := range t.removed {
+		previous, err := keep(f)
+		if err != nil {
+			return t.abort(done, fmt.Errorf("keeping previous %q: %w", f, err))
+		}
+
+		if previous == "" {
+			continue
+		}
+
+		if err := os.Remove(f.String()); err != nil {
+			os.Remove(previous)
+
+			return t.abort(done, fmt.Errorf("removing %q: %w", f, err))
+		}
+
+		done = append(done, replaced{target: f, previous: previous})
+	}
+
+	var dirs []string
+
+	for _, r := range done {
+		if r.previous != "" {
+			os.Remove(r.previous)
+		}
+
+		if dir := r.target.Dir().Path(); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	// Persist the renames. Not all platforms support syncing folders, which is not an error.
+	for _, dir := range dirs {
+		if d, err := os.Open(dir); err == nil {
+			d.Sync()
+			d.Close()
+		}
+	}
+
+	t.staged = nil
+	t.removed = nil
+
+	return nil
+}
+
+// Discard removes the staged files, leaving the targets untouched.
+// It is safe to call after Commit.
+func (t *Transaction) Discard() {
+	for _, s := range t.staged {
+		os.Remove(s.temporary)
+	}
+
+	t.staged = nil
+	t.removed = nil
+}
+
+// abort restores the replaced targets in reverse order, and discards the transaction.
+func (t *Transaction) abort(done []replaced, err error) error {
+	var errs []error
+
+	for _, r := range slices.Backward(done) {
+		if r.previous == "" {
+			errs = append(errs, ignoreNotExist(os.Remove(r.target.String())))
+		} else {
+			errs = append(errs, os.Rename(r.previous, r.target.String()))
+		}
+	}
+
+	t.Discard()
+
+	if restoreErr := errors.Join(errs...); restoreErr != nil {
+		return fmt.Errorf("%w (restoring previous files: %w)", err, restoreErr)
+	}
+
+	return err
+}
+
+// source returns the staged copy of the file, if it is staged in the transaction.
+func (t *Transaction) source(f File) string {
+	for _, s := range t.staged {
+		if s.target == f {
+			return s.temporary
+		}
+	}
+
+	return f.String()
+}
+
+// reserve returns an unused temporary name next to the target.
+func (t *Transaction) reserve(target File) (string, error) {
+	reserved, err := os.CreateTemp(target.Dir().Path(), "."+filepath.Base(target.String())+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("staging %q: %w", target, err)
+	}
+
+	reserved.Close()
+
+	return reserved.Name(), os.Remove(reserved.Name())
+}
+
+// keep creates a link to the current content of the target (a hard link, or a copy of a symlink),
+// to restore it if the transaction fails. It returns "" if the target does not exist.
+func keep(target File) (string, error) {
+	info, err := os.Lstat(target.String())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	previous := fmt.Sprintf("%s.%d.previous", target, os.Getpid())
+
+	if err := ignoreNotExist(os.Remove(previous)); err != nil {
+		return "", err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		destination, err := os.Readlink(target.String())
+		if err != nil {
+			return "", err
+		}
+
+		return previous, os.Symlink(destination, previous)
+	}
+
+	return previous, os.Link(target.String(), previous)
+}
+
+// ignoreNotExist drops errors caused by files which do not exist.
+func ignoreNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
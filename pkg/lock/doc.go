@@ -0,0 +1,3 @@
+// Package lock provides advisory file locks, to serialize processes working on the same files.
+// The lock file records the process ID of the holder, to report who is holding the lock.
+package lock
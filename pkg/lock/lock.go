@@ -0,0 +1,98 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLocked is returned when the lock is still held by another process after waiting.
+var ErrLocked = errors.New("locked by another process")
+
+// poll is the interval at which a held lock is retried.
+const poll = 100 * time.Millisecond
+
+// Lock is an exclusive advisory lock on a file, held until released or the process exits.
+type Lock struct {
+	file *os.File
+}
+
+// Acquire locks the file at the given path, creating it and its folder if needed.
+// If another process holds the lock, it is retried until the timeout has passed, after which
+// an error wrapping ErrLocked is returned. A timeout of 0 does not wait, a negative one waits indefinitely.
+// Waiting stops with the context's error when the context is cancelled.
+func Acquire(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating folder for lock %q: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock %q: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+
+			return nil, fmt.Errorf("locking %q: %w", path, err)
+		}
+
+		if locked {
+			break
+		}
+
+		if timeout >= 0 && !time.Now().Before(deadline) {
+			f.Close()
+
+			return nil, fmt.Errorf("%w: %q", ErrLocked, path)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+
+			return nil, ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+
+	// Record the holder, for the messages of the processes waiting for it.
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+
+	return &Lock{file: f}, nil
+}
+
+// Release unlocks and closes the file. The file itself is kept, as other processes may be waiting on it.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+
+	return errors.Join(unlock(l.file), l.file.Close())
+}
+
+// Holder returns the process ID recorded in the lock file at the given path, or 0 if unknown.
+func Holder(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+
+	return pid
+}
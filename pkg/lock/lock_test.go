@@ -0,0 +1 @@
+package lock_test
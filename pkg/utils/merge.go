@@ -0,0 +1,45 @@
+package utils
+
+import "reflect"
+
+// MergeNonZero deep-merges src into dst, overwriting the values in dst with the non-zero values in src.
+// Structs are merged field by field and maps key by key, while all other values, including slices, are replaced.
+// Unexported fields are left untouched.
+func MergeNonZero[T any](dst *T, src T) {
+	mergeNonZero(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src))
+}
+
+// mergeNonZero merges the src value into the settable dst value.
+func mergeNonZero(dst, src reflect.Value) {
+	if src.IsZero() {
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				mergeNonZero(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Map:
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+
+		iter := src.MapRange()
+		for iter.Next() {
+			// Map elements are not addressable, so they are merged through a copy
+			merged := reflect.New(src.Type().Elem()).Elem()
+
+			if existing := dst.MapIndex(iter.Key()); existing.IsValid() {
+				merged.Set(existing)
+			}
+
+			mergeNonZero(merged, iter.Value())
+			dst.SetMapIndex(iter.Key(), merged)
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -31,6 +31,14 @@ func SetSliceIfNil[S ~[]T, T any](input *S, values ...T) {
 	}
 }
 
+// SetPointerIfNil sets input to point to a copy of the value if input is nil.
+// This allows distinguishing values set explicitly to their zero value from unset ones.
+func SetPointerIfNil[T any](input **T, value T) {
+	if *input == nil {
+		*input = &value
+	}
+}
+
 // IsEmpty checks if the input value is empty.
 // S must be a comparable type.
 func IsEmpty[S comparable](input S) bool {
@@ -1 +1,97 @@
 package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/idelchi/godyl/pkg/utils"
+)
+
+type inner struct {
+	Name  string
+	Count int
+}
+
+type config struct {
+	Name    string
+	Enabled bool
+	Tags    []string
+	Inner   inner
+	Values  map[string]inner
+	hidden  string
+	Pointer *inner
+}
+
+func TestMergeNonZero(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		dst  config
+		src  config
+		want config
+	}{
+		{
+			name: "zero source keeps destination",
+			dst:  config{Name: "dst", Tags: []string{"a"}, Inner: inner{Name: "in", Count: 1}},
+			src:  config{},
+			want: config{Name: "dst", Tags: []string{"a"}, Inner: inner{Name: "in", Count: 1}},
+		},
+		{
+			name: "scalars are overwritten",
+			dst:  config{Name: "dst"},
+			src:  config{Name: "src", Enabled: true},
+			want: config{Name: "src", Enabled: true},
+		},
+		{
+			name: "slices are replaced",
+			dst:  config{Tags: []string{"a", "b"}},
+			src:  config{Tags: []string{"c"}},
+			want: config{Tags: []string{"c"}},
+		},
+		{
+			name: "structs are merged by field",
+			dst:  config{Inner: inner{Name: "dst", Count: 1}},
+			src:  config{Inner: inner{Count: 2}},
+			want: config{Inner: inner{Name: "dst", Count: 2}},
+		},
+		{
+			name: "maps are merged by key",
+			dst:  config{Values: map[string]inner{"a": {Name: "a", Count: 1}, "b": {Name: "b"}}},
+			src:  config{Values: map[string]inner{"a": {Count: 2}, "c": {Name: "c"}}},
+			want: config{Values: map[string]inner{"a": {Name: "a", Count: 2}, "b": {Name: "b"}, "c": {Name: "c"}}},
+		},
+		{
+			name: "nil destination maps are created",
+			dst:  config{},
+			src:  config{Values: map[string]inner{"a": {Name: "a"}}},
+			want: config{Values: map[string]inner{"a": {Name: "a"}}},
+		},
+		{
+			name: "pointers are replaced",
+			dst:  config{Pointer: &inner{Name: "dst", Count: 1}},
+			src:  config{Pointer: &inner{Name: "src"}},
+			want: config{Pointer: &inner{Name: "src"}},
+		},
+		{
+			name: "unexported fields are left untouched",
+			dst:  config{hidden: "dst"},
+			src:  config{Name: "src", hidden: "src"},
+			want: config{Name: "src", hidden: "dst"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.dst
+
+			utils.MergeNonZero(&got, tt.src)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeNonZero() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}